@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int64
+		size        int
+		unknownLen  bool
+		wantStatus  int
+		wantHandler bool
+	}{
+		{name: "under the limit", limit: 10, size: 5, wantStatus: http.StatusOK, wantHandler: true},
+		{name: "at the limit", limit: 10, size: 10, wantStatus: http.StatusOK, wantHandler: true},
+		{name: "declared length over the limit", limit: 10, size: 11, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "streamed body over the limit", limit: 10, size: 11, unknownLen: true, wantStatus: http.StatusRequestEntityTooLarge, wantHandler: true},
+		{name: "zero limit is unlimited", limit: 0, size: 1 << 20, wantStatus: http.StatusOK, wantHandler: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			r := gin.New()
+			r.Use(MaxBodySize(tt.limit))
+			r.POST("/upload", func(c *gin.Context) {
+				called = true
+				b, err := io.ReadAll(c.Request.Body)
+				if err != nil {
+					return
+				}
+				c.String(http.StatusOK, strconv.Itoa(len(b)))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", tt.size)))
+			if tt.unknownLen {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if called != tt.wantHandler {
+				t.Errorf("handler called = %v, want %v", called, tt.wantHandler)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != strconv.Itoa(tt.size) {
+				t.Errorf("handler read %s bytes, want %d", w.Body.String(), tt.size)
+			}
+		})
+	}
+}
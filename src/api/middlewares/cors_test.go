@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"automart/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs req through a router using mw in front of a 200 handler on every
+// method.
+func serve(mw gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(mw)
+	r.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	explicit := config.CorsConfig{
+		AllowedOrigins:   []string{"https://app.automart.test"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	wildcard := config.CorsConfig{AllowedOrigins: []string{"*"}}
+
+	tests := []struct {
+		name        string
+		cfg         config.CorsConfig
+		method      string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+		headers     map[string]string
+	}{
+		{
+			name: "preflight from an allowed origin", cfg: explicit, method: http.MethodOptions,
+			origin: "https://app.automart.test", preflight: true, status: http.StatusNoContent,
+			allowOrigin: "https://app.automart.test",
+			headers: map[string]string{
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Origin, Content-Type, Accept, Authorization",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name: "preflight from another origin", cfg: explicit, method: http.MethodOptions,
+			origin: "https://evil.test", preflight: true, status: http.StatusForbidden,
+		},
+		{
+			name: "simple request from another origin", cfg: explicit, method: http.MethodGet,
+			origin: "https://evil.test", status: http.StatusOK,
+		},
+		{
+			name: "simple request from an allowed origin", cfg: explicit, method: http.MethodGet,
+			origin: "https://app.automart.test", status: http.StatusOK, allowOrigin: "https://app.automart.test",
+		},
+		{
+			name: "wildcard", cfg: wildcard, method: http.MethodOptions, origin: "https://any.test",
+			preflight: true, status: http.StatusNoContent, allowOrigin: "*",
+			headers: map[string]string{"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE, OPTIONS"},
+		},
+		{
+			name: "same-origin request", cfg: explicit, method: http.MethodGet, status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/cars", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+
+			w := serve(NewCorsMiddleware(tt.cfg), req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"automart/config"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket refills KEYS[1] at ARGV[1] tokens per millisecond up to ARGV[2]
+// tokens and takes one token if available. It returns {allowed, retryAfterMs}.
+// Redis' own clock is used so every app instance shares the same time base.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + (now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return {allowed, retry}
+`)
+
+// NewRateLimiter returns a middleware limiting each client IP to
+// cfg.RequestsPerMinute requests, allowing bursts of up to cfg.Burst
+// requests (RequestsPerMinute when Burst is zero). Throttled requests get
+// 429 with a Retry-After header. When cfg.Enabled is false or rdb is nil
+// (Redis marked optional and down at startup) it passes every request
+// through; when Redis is unreachable it fails open.
+func NewRateLimiter(cfg config.RateLimitConfig, rdb redis.UniversalClient) gin.HandlerFunc {
+	if !cfg.Enabled || rdb == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = cfg.RequestsPerMinute
+	}
+	perMs := float64(cfg.RequestsPerMinute) / float64(time.Minute.Milliseconds())
+
+	return func(c *gin.Context) {
+		key := "ratelimit:" + c.ClientIP()
+		res, err := tokenBucket.Run(c.Request.Context(), rdb, []string{key}, perMs, burst).Int64Slice()
+		if err != nil {
+			log.Printf("rate limiter: %v; letting request through", err)
+			c.Next()
+			return
+		}
+
+		if res[0] == 0 {
+			retryAfter := (res[1] + 999) / 1000
+			c.Header("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
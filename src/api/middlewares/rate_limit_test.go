@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"automart/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRateLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.RateLimitConfig
+		noRedis   bool
+		redisDown bool
+		requests  int
+		allowed   int
+	}{
+		{name: "burst then 429", cfg: config.RateLimitConfig{Enabled: true, RequestsPerMinute: 60, Burst: 3}, requests: 5, allowed: 3},
+		{name: "burst defaults to the rate", cfg: config.RateLimitConfig{Enabled: true, RequestsPerMinute: 2}, requests: 4, allowed: 2},
+		{name: "disabled", cfg: config.RateLimitConfig{RequestsPerMinute: 1}, requests: 5, allowed: 5},
+		{name: "no redis", cfg: config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1}, noRedis: true, requests: 5, allowed: 5},
+		{name: "fails open when redis is down", cfg: config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1}, redisDown: true, requests: 3, allowed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rdb redis.UniversalClient
+			if !tt.noRedis {
+				mr := miniredis.RunT(t)
+				rdb = redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialerRetries: 1})
+				t.Cleanup(func() { _ = rdb.Close() })
+				if tt.redisDown {
+					mr.Close()
+				}
+			}
+			mw := NewRateLimiter(tt.cfg, rdb)
+
+			allowed := 0
+			for i := 0; i < tt.requests; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/cars", nil)
+				req.RemoteAddr = "203.0.113.7:4000"
+				w := serve(mw, req)
+				switch w.Code {
+				case http.StatusOK:
+					allowed++
+				case http.StatusTooManyRequests:
+					if got := w.Header().Get("Retry-After"); got == "" || got == "0" {
+						t.Errorf("429 carries Retry-After %q, want a positive number of seconds", got)
+					}
+				default:
+					t.Fatalf("request %d got status %d", i+1, w.Code)
+				}
+			}
+			if allowed != tt.allowed {
+				t.Errorf("%d of %d requests allowed, want %d", allowed, tt.requests, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestRateLimiterIsPerClientIP(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	mw := NewRateLimiter(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1}, rdb)
+
+	requests := []struct {
+		ip     string
+		status int
+	}{
+		{ip: "203.0.113.7", status: http.StatusOK},
+		{ip: "203.0.113.7", status: http.StatusTooManyRequests},
+		{ip: "198.51.100.9", status: http.StatusOK},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest(http.MethodGet, "/cars", nil)
+		req.RemoteAddr = r.ip + ":4000"
+		if w := serve(mw, req); w.Code != r.status {
+			t.Errorf("request from %s got %d, want %d", r.ip, w.Code, r.status)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Recovery turns a panicking handler into a 500 response and logs the panic
+// value, request ID and stack trace at error level. The response only says
+// "internal server error"; in gin debug mode it also carries the panic value.
+// Panics with http.ErrAbortHandler are re-raised, since net/http uses them
+// to abort a response on purpose.
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			logger.Error("panic recovered",
+				zap.String("request_id", RequestIDFromContext(c.Request.Context())),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Any("panic", rec),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			body := gin.H{"error": "internal server error"}
+			if gin.IsDebugging() {
+				body["panic"] = fmt.Sprint(rec)
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, body)
+		}()
+		c.Next()
+	}
+}
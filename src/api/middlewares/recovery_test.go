@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantPanic  string // panic value expected in the response body
+		wantLogged bool
+	}{
+		{name: "no panic", mode: gin.ReleaseMode, handler: func(c *gin.Context) { c.Status(http.StatusOK) }, wantStatus: http.StatusOK},
+		{name: "panic in release mode", mode: gin.ReleaseMode, handler: func(c *gin.Context) { panic("db password is hunter2") }, wantStatus: http.StatusInternalServerError, wantLogged: true},
+		{name: "panic in debug mode", mode: gin.DebugMode, handler: func(c *gin.Context) { panic(errors.New("nil map")) }, wantStatus: http.StatusInternalServerError, wantPanic: "nil map", wantLogged: true},
+		{
+			name: "panic after writing keeps the status",
+			mode: gin.ReleaseMode,
+			handler: func(c *gin.Context) {
+				c.String(http.StatusAccepted, "partial")
+				panic("late")
+			},
+			wantStatus: http.StatusAccepted,
+			wantLogged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(tt.mode)
+			defer gin.SetMode(gin.TestMode)
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			r := gin.New()
+			r.Use(RequestID(), Recovery(zap.New(core)))
+			r.GET("/", tt.handler)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, "req-42")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if !tt.wantLogged {
+				if logs.Len() != 0 {
+					t.Errorf("logged %d entries without a panic", logs.Len())
+				}
+				return
+			}
+
+			entries := logs.FilterMessage("panic recovered").All()
+			if len(entries) != 1 || entries[0].Level != zapcore.ErrorLevel {
+				t.Fatalf("want one error entry, got %+v", logs.All())
+			}
+			fields := entries[0].ContextMap()
+			if fields["request_id"] != "req-42" {
+				t.Errorf("request_id = %v, want req-42", fields["request_id"])
+			}
+			if stack, _ := fields["stack"].(string); !strings.Contains(stack, "runtime/debug.Stack") {
+				t.Errorf("stack field does not hold a stack trace: %.200q", stack)
+			}
+
+			if tt.wantStatus != http.StatusInternalServerError {
+				return
+			}
+			var body map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q is not JSON: %v", w.Body.String(), err)
+			}
+			if body["error"] != "internal server error" || body["panic"] != tt.wantPanic {
+				t.Errorf("body = %v, want the generic error with panic %q", body, tt.wantPanic)
+			}
+			if strings.Contains(w.Body.String(), "hunter2") {
+				t.Errorf("release response leaks the panic value: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecoveryReraisesErrAbortHandler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(Recovery(zap.New(core)))
+	r.GET("/", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", rec)
+		}
+		if logs.Len() != 0 {
+			t.Errorf("logged an intentional abort: %+v", logs.All())
+		}
+	}()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Error("ServeHTTP returned normally")
+}
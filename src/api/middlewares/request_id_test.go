@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "incoming header honoured", incoming: "req-42", keep: true},
+		{name: "missing header generates one"},
+		{name: "control characters are replaced", incoming: "req\n42"},
+		{name: "overlong IDs are replaced", incoming: strings.Repeat("a", maxRequestIDLen+1)},
+		{name: "longest accepted ID", incoming: strings.Repeat("a", maxRequestIDLen), keep: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) { seen = RequestIDFromContext(c.Request.Context()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			echoed := w.Header().Get(RequestIDHeader)
+			if echoed != seen {
+				t.Errorf("response header %q differs from the context ID %q", echoed, seen)
+			}
+			if tt.keep {
+				if seen != tt.incoming {
+					t.Errorf("ID = %q, want the incoming %q", seen, tt.incoming)
+				}
+				return
+			}
+			if _, err := uuid.Parse(seen); err != nil {
+				t.Errorf("generated ID %q is not a UUID", seen)
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContextWithoutMiddleware(t *testing.T) {
+	if id := RequestIDFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); id != "" {
+		t.Errorf("RequestIDFromContext = %q, want empty", id)
+	}
+}
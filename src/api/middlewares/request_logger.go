@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"automart/pkg/logging"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultSkipPaths are not logged unless RequestLogger gets its own list:
+// probes and scrapes would otherwise drown out real traffic.
+var defaultSkipPaths = []string{"/api/v1/health", "/api/v2/health", "/healthz", "/readyz", "/metrics"}
+
+// RequestLogger logs one structured entry per request with its method,
+// path, status, latency, client IP and request ID (so it must run after
+// RequestID). 5xx responses are logged at error level, 4xx at warn and
+// everything else at info. Requests taking longer than a positive
+// slowThreshold are logged at warn level at least, with slow=true. Requests
+// whose path starts with one of skipPaths are not logged; without skipPaths
+// the default health, probe and metrics paths are skipped.
+//
+// Handlers get a logger tagged with the request ID and route through
+// logging.FromContext(c.Request.Context()), skipped paths included.
+func RequestLogger(logger *zap.Logger, slowThreshold time.Duration, skipPaths ...string) gin.HandlerFunc {
+	if len(skipPaths) == 0 {
+		skipPaths = defaultSkipPaths
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		reqLogger := logger.With(
+			zap.String("request_id", RequestIDFromContext(c.Request.Context())),
+			zap.String("route", c.FullPath()),
+		)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), reqLogger))
+
+		for _, p := range skipPaths {
+			if strings.HasPrefix(path, p) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+		status := c.Writer.Status()
+		latency := time.Since(start)
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.Int("status", status),
+			zap.Duration("latency", latency),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+		level := statusLevel(status)
+		if slowThreshold > 0 && latency > slowThreshold {
+			fields = append(fields, zap.Bool("slow", true))
+			if level < zapcore.WarnLevel {
+				level = zapcore.WarnLevel
+			}
+		}
+		reqLogger.Log(level, "request", fields...)
+	}
+}
+
+func statusLevel(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
@@ -0,0 +1,167 @@
+package middlewares
+
+import (
+	"automart/pkg/logging"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// loggedRouter returns a router with RequestID and RequestLogger in front of
+// handlers answering with the status in their path, and the observed logs.
+func loggedRouter(slow time.Duration, skipPaths ...string) (*gin.Engine, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(RequestID(), RequestLogger(zap.New(core), slow, skipPaths...))
+	for path, status := range map[string]int{"/ok": http.StatusOK, "/missing": http.StatusNotFound, "/boom": http.StatusInternalServerError} {
+		r.GET(path, func(c *gin.Context) { c.Status(status) })
+	}
+	for path, status := range map[string]int{"/slow": http.StatusOK, "/slow/boom": http.StatusInternalServerError} {
+		r.GET(path, func(c *gin.Context) {
+			time.Sleep(20 * time.Millisecond)
+			c.Status(status)
+		})
+	}
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r, logs
+}
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		path   string
+		status int
+		level  zapcore.Level
+	}{
+		{path: "/ok", status: http.StatusOK, level: zapcore.InfoLevel},
+		{path: "/missing", status: http.StatusNotFound, level: zapcore.WarnLevel},
+		{path: "/boom", status: http.StatusInternalServerError, level: zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r, logs := loggedRouter(0)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = "203.0.113.7:4000"
+			req.Header.Set(RequestIDHeader, "req-42")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			entries := logs.All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+			e := entries[0]
+			if e.Level != tt.level {
+				t.Errorf("level = %s, want %s", e.Level, tt.level)
+			}
+			fields := e.ContextMap()
+			want := map[string]interface{}{
+				"method":     "GET",
+				"path":       tt.path,
+				"status":     int64(tt.status),
+				"client_ip":  "203.0.113.7",
+				"request_id": "req-42",
+				"route":      tt.path,
+			}
+			for k, v := range want {
+				if fields[k] != v {
+					t.Errorf("field %s = %v, want %v", k, fields[k], v)
+				}
+			}
+			if _, ok := fields["latency"]; !ok {
+				t.Error("latency not logged")
+			}
+		})
+	}
+}
+
+func TestRequestLoggerSlowRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		path      string
+		level     zapcore.Level
+		slow      bool
+	}{
+		{name: "fast request", threshold: 10 * time.Millisecond, path: "/ok", level: zapcore.InfoLevel},
+		{name: "slow request", threshold: 10 * time.Millisecond, path: "/slow", level: zapcore.WarnLevel, slow: true},
+		{name: "slow 5xx stays at error", threshold: 10 * time.Millisecond, path: "/slow/boom", level: zapcore.ErrorLevel, slow: true},
+		{name: "below a higher threshold", threshold: time.Minute, path: "/slow", level: zapcore.InfoLevel},
+		{name: "zero threshold disables it", threshold: 0, path: "/slow", level: zapcore.InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, logs := loggedRouter(tt.threshold)
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			entries := logs.All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+			if entries[0].Level != tt.level {
+				t.Errorf("level = %s, want %s", entries[0].Level, tt.level)
+			}
+			slow, ok := entries[0].ContextMap()["slow"]
+			if ok != tt.slow || (ok && slow != true) {
+				t.Errorf("slow field = %v (present %v), want present %v", slow, ok, tt.slow)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerSkipPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		skipPaths []string
+		path      string
+		logged    bool
+	}{
+		{name: "default skips probes", path: "/healthz"},
+		{name: "default skips metrics", path: "/metrics"},
+		{name: "default logs others", path: "/ok", logged: true},
+		{name: "custom list replaces the default", skipPaths: []string{"/ok"}, path: "/healthz", logged: true},
+		{name: "custom list skips its paths", skipPaths: []string{"/ok"}, path: "/ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, logs := loggedRouter(0, tt.skipPaths...)
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if got := logs.Len() > 0; got != tt.logged {
+				t.Errorf("%s logged: %v, want %v", tt.path, got, tt.logged)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerInjectsRequestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(RequestID(), RequestLogger(zap.New(core), 0))
+	r.GET("/cars/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("loading car")
+	})
+	r.GET("/healthz", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("probe")
+	})
+
+	for _, path := range []string{"/cars/7", "/healthz"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(RequestIDHeader, "req-42")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for _, msg := range []string{"loading car", "probe"} {
+		entries := logs.FilterMessage(msg).All()
+		if len(entries) != 1 || entries[0].ContextMap()["request_id"] != "req-42" {
+			t.Errorf("handler entry %q = %v, want one entry tagged with req-42", msg, entries)
+		}
+	}
+	if got := logs.FilterMessage("loading car").All(); len(got) == 1 && got[0].ContextMap()["route"] != "/cars/:id" {
+		t.Errorf("route = %v, want /cars/:id", got[0].ContextMap()["route"])
+	}
+}
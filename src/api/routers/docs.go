@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"automart/config"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
+)
+
+// RegisterDocs mounts the swagger UI under cfg.Path (e.g. /swagger/index.html)
+// when cfg.Enabled is set; otherwise nothing is mounted and the path 404s.
+// The UI serves the spec generated by `swag init` once the generated docs
+// package is imported; until then a placeholder spec titled cfg.Title is
+// served.
+func RegisterDocs(r gin.IRouter, cfg config.DocsConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	if swag.GetSwagger(swag.Name) == nil {
+		swag.Register(swag.Name, placeholderSpec{title: cfg.Title})
+	}
+	r.GET(strings.TrimSuffix(cfg.Path, "/")+"/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+}
+
+// placeholderSpec is an empty Swagger 2.0 document.
+type placeholderSpec struct {
+	title string
+}
+
+func (s placeholderSpec) ReadDoc() string {
+	doc, _ := json.Marshal(map[string]interface{}{
+		"swagger": "2.0",
+		"info":    map[string]string{"title": s.title, "version": "1.0"},
+		"paths":   map[string]interface{}{},
+	})
+	return string(doc)
+}
@@ -0,0 +1,50 @@
+package routers
+
+import (
+	"automart/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// get serves a GET request for path through r.
+func get(r http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestRegisterDocs(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.DocsConfig
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "UI when enabled", cfg: config.DocsConfig{Enabled: true, Path: "/swagger", Title: "AutoMart API"}, path: "/swagger/index.html", wantStatus: http.StatusOK, wantBody: "swagger-ui"},
+		{name: "spec when enabled", cfg: config.DocsConfig{Enabled: true, Path: "/swagger", Title: "AutoMart API"}, path: "/swagger/doc.json", wantStatus: http.StatusOK, wantBody: `"swagger":"2.0"`},
+		{name: "custom path with trailing slash", cfg: config.DocsConfig{Enabled: true, Path: "/api-docs/"}, path: "/api-docs/index.html", wantStatus: http.StatusOK},
+		{name: "404 when disabled", cfg: config.DocsConfig{Path: "/swagger"}, path: "/swagger/index.html", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			RegisterDocs(r, tt.cfg)
+			w := get(r, tt.path)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.path, w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("GET %s body does not contain %q", tt.path, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"automart/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterStatic serves the files in cfg.Dir under cfg.URLPrefix when
+// cfg.Enabled is set. Requests are resolved by http.Dir, which cleans the
+// path and rejects ".." segments, so nothing outside cfg.Dir can be read;
+// directory listings are disabled.
+func RegisterStatic(r gin.IRouter, cfg config.StaticConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	r.StaticFS(cfg.URLPrefix, gin.Dir(cfg.Dir, false))
+}
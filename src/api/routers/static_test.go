@@ -0,0 +1,56 @@
+package routers
+
+import (
+	"automart/config"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterStatic(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	if err := os.MkdirAll(filepath.Join(dir, "cars"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cars", "1.jpg"), []byte("jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "serves a file", enabled: true, path: "/static/cars/1.jpg", wantStatus: http.StatusOK, wantBody: "jpeg"},
+		{name: "missing file", enabled: true, path: "/static/cars/2.jpg", wantStatus: http.StatusNotFound},
+		{name: "no directory listing", enabled: true, path: "/static/cars/", wantStatus: http.StatusNotFound},
+		{name: "blocks traversal", enabled: true, path: "/static/../secret.txt", wantStatus: http.StatusNotFound},
+		{name: "blocks encoded traversal", enabled: true, path: "/static/%2e%2e/secret.txt", wantStatus: http.StatusNotFound},
+		{name: "disabled", path: "/static/cars/1.jpg", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			RegisterStatic(r, config.StaticConfig{Enabled: tt.enabled, Dir: dir, URLPrefix: "/static"})
+			w := get(r, tt.path)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.path, w.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("GET %s body = %q, want %q", tt.path, w.Body.String(), tt.wantBody)
+			}
+			if w.Body.String() == "secret" {
+				t.Errorf("GET %s served a file outside Static.Dir", tt.path)
+			}
+		})
+	}
+}
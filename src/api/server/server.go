@@ -0,0 +1,166 @@
+package server
+
+import (
+	"automart/config"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults applied when the matching ServerConfig field is zero.
+const (
+	defaultShutdownTimeout   = 10 * time.Second
+	defaultReadTimeout       = 15 * time.Second
+	defaultWriteTimeout      = 15 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+)
+
+// NewHTTPServer builds the HTTP server listening on cfg.Address(). The
+// read, write, idle and header timeouts protect against slow or stalled
+// clients; each falls back to a default when left at zero.
+func NewHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	srv := &http.Server{
+		Addr:              cfg.Address(),
+		Handler:           handler,
+		ReadTimeout:       orDefault(cfg.ReadTimeout, defaultReadTimeout),
+		WriteTimeout:      orDefault(cfg.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       orDefault(cfg.IdleTimeout, defaultIdleTimeout),
+		ReadHeaderTimeout: orDefault(cfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+	}
+	if cfg.EnableTLS {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return srv
+}
+
+func orDefault(d, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return d
+}
+
+// ApplyGinMode switches gin to the mode configured in cfg.RunMode.
+func ApplyGinMode(cfg config.ServerConfig) {
+	gin.SetMode(cfg.GinMode())
+}
+
+// NewEngine returns a gin engine without middleware that honours
+// X-Forwarded-For only from cfg.TrustedProxies, so ClientIP, and with it rate
+// limiting and request logs, sees the real client behind a load balancer
+// while other callers cannot spoof it. With no trusted proxies the peer
+// address is used; gin on its own would trust every proxy.
+func NewEngine(cfg config.ServerConfig) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid Server.TrustedProxies: %w", err)
+	}
+	return r, nil
+}
+
+// AdminHandler serves metrics, through the handler returned by
+// metrics.NewMetricsRegistry, and with cfg.EnablePprof the net/http/pprof
+// profiles under /debug/pprof/. A nil metrics handler, for when metrics are
+// disabled, leaves only the profiles.
+func AdminHandler(cfg config.ServerConfig, metrics http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	if metrics != nil {
+		mux.Handle("/", metrics)
+	}
+	if cfg.EnablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return mux
+}
+
+// StartAdminServer serves AdminHandler on cfg.MetricsPort in the background,
+// keeping metrics and profiles off the public API port, and returns the
+// server so it can be shut down with the rest. The port is bound before it
+// returns, so a failure to listen, such as the port being taken, is
+// returned; it is served over plain HTTP.
+func StartAdminServer(cfg config.ServerConfig, registry http.Handler) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              net.JoinHostPort(cfg.Host, cfg.MetricsPort),
+		Handler:           AdminHandler(cfg, registry),
+		ReadHeaderTimeout: orDefault(cfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+	}
+	l, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("admin server: %w", err)
+	}
+	log.Printf("server: admin listening on %s", srv.Addr)
+	go func() {
+		if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("server: admin: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+// Serve listens on srv until it is shut down, returning http.ErrServerClosed
+// once srv.Shutdown is called. An invalid listen port is reported before
+// anything is started. With cfg.EnableTLS the server only accepts HTTPS,
+// using cfg.TLSCertFile and cfg.TLSKeyFile.
+func Serve(srv *http.Server, cfg config.ServerConfig) error {
+	if _, err := cfg.PortInt(); err != nil {
+		return err
+	}
+	if cfg.EnableTLS {
+		log.Printf("server: listening on %s (TLS)", srv.Addr)
+		return srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+	log.Printf("server: listening on %s", srv.Addr)
+	return srv.ListenAndServe()
+}
+
+// Run serves srv until ctx is cancelled or the process receives SIGINT or
+// SIGTERM, then shuts it down gracefully, giving in-flight requests up to
+// cfg.ShutdownTimeout to finish. See Serve for the listener setup. It suits
+// a binary with no other resources to close; InitServer registers
+// srv.Shutdown with a lifecycle.Lifecycle instead.
+func Run(ctx context.Context, srv *http.Server, cfg config.ServerConfig) error {
+	if _, err := cfg.PortInt(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- Serve(srv, cfg) }()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownTimeout := orDefault(cfg.ShutdownTimeout, defaultShutdownTimeout)
+	log.Printf("server: shutting down (timeout %s)", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// freePort returns a port nothing is listening on.
+func freePort(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+// waitListening blocks until addr accepts connections.
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("nothing listening on %s", addr)
+}
+
+func TestRunShutsDownGracefully(t *testing.T) {
+	tests := []struct {
+		name      string
+		handle    time.Duration // how long the in-flight request takes
+		timeout   time.Duration
+		wantErr   string
+		wantReply bool
+	}{
+		{name: "in-flight request completes", handle: 200 * time.Millisecond, timeout: 2 * time.Second, wantReply: true},
+		{name: "shutdown timeout exceeded", handle: 2 * time.Second, timeout: 100 * time.Millisecond, wantErr: "shutdown: context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ServerConfig{Host: "127.0.0.1", Port: freePort(t), ShutdownTimeout: tt.timeout}
+			started := make(chan struct{})
+			srv := NewHTTPServer(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				time.Sleep(tt.handle)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ran := make(chan error, 1)
+			go func() { ran <- Run(ctx, srv, cfg) }()
+			waitListening(t, srv.Addr)
+
+			responses := make(chan int, 1)
+			go func() {
+				resp, err := http.Get("http://" + srv.Addr)
+				if err != nil {
+					responses <- 0
+					return
+				}
+				resp.Body.Close()
+				responses <- resp.StatusCode
+			}()
+			<-started
+
+			start := time.Now()
+			cancel()
+			err := <-ran
+			if elapsed := time.Since(start); elapsed > tt.timeout+time.Second {
+				t.Errorf("Run returned %s after cancel, over the %s timeout", elapsed, tt.timeout)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Run = %v, want %q", err, tt.wantErr)
+			}
+			if tt.wantReply {
+				if status := <-responses; status != http.StatusNoContent {
+					t.Errorf("in-flight request got status %d, want %d", status, http.StatusNoContent)
+				}
+			}
+		})
+	}
+}
+
+func TestRunReportsServeErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	tests := []struct {
+		name    string
+		cfg     config.ServerConfig
+		wantErr string
+	}{
+		{name: "invalid port", cfg: config.ServerConfig{Port: "0"}, wantErr: `invalid Server.Port "0"`},
+		{name: "port in use", cfg: config.ServerConfig{Host: "127.0.0.1", Port: port}, wantErr: "serve: listen tcp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Run(context.Background(), NewHTTPServer(tt.cfg, http.NotFoundHandler()), tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServeRejectsInvalidPort(t *testing.T) {
+	for _, port := range []string{"", "0", "http", "70000"} {
+		cfg := config.ServerConfig{Port: port}
+		if err := Serve(NewHTTPServer(cfg, http.NotFoundHandler()), cfg); err == nil || errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Serve with port %q = %v, want a port error", port, err)
+		}
+	}
+}
+
+func TestNewHTTPServerTimeouts(t *testing.T) {
+	tests := []struct {
+		name                          string
+		cfg                           config.ServerConfig
+		read, write, idle, readHeader time.Duration
+	}{
+		{
+			name:       "defaults",
+			read:       defaultReadTimeout,
+			write:      defaultWriteTimeout,
+			idle:       defaultIdleTimeout,
+			readHeader: defaultReadHeaderTimeout,
+		},
+		{
+			name: "configured",
+			cfg: config.ServerConfig{
+				ReadTimeout:       time.Second,
+				WriteTimeout:      2 * time.Second,
+				IdleTimeout:       3 * time.Second,
+				ReadHeaderTimeout: 4 * time.Second,
+			},
+			read:       time.Second,
+			write:      2 * time.Second,
+			idle:       3 * time.Second,
+			readHeader: 4 * time.Second,
+		},
+		{
+			name:       "partly configured",
+			cfg:        config.ServerConfig{WriteTimeout: time.Minute},
+			read:       defaultReadTimeout,
+			write:      time.Minute,
+			idle:       defaultIdleTimeout,
+			readHeader: defaultReadHeaderTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewHTTPServer(tt.cfg, http.NotFoundHandler())
+			if srv.ReadTimeout != tt.read || srv.WriteTimeout != tt.write ||
+				srv.IdleTimeout != tt.idle || srv.ReadHeaderTimeout != tt.readHeader {
+				t.Errorf("timeouts = %s/%s/%s/%s, want %s/%s/%s/%s",
+					srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout, srv.ReadHeaderTimeout,
+					tt.read, tt.write, tt.idle, tt.readHeader)
+			}
+		})
+	}
+}
+
+func TestApplyGinMode(t *testing.T) {
+	t.Cleanup(func() { gin.SetMode(gin.TestMode) })
+	for _, tt := range []struct{ runMode, want string }{
+		{runMode: "release", want: gin.ReleaseMode},
+		{runMode: "test", want: gin.TestMode},
+		{runMode: "", want: gin.DebugMode},
+	} {
+		ApplyGinMode(config.ServerConfig{RunMode: tt.runMode})
+		if got := gin.Mode(); got != tt.want {
+			t.Errorf("RunMode %q set gin mode %q, want %q", tt.runMode, got, tt.want)
+		}
+	}
+}
+
+func TestNewEngineTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+		wantErr bool
+	}{
+		{name: "trusted proxy IP", proxies: []string{"192.0.2.1"}, want: "203.0.113.7"},
+		{name: "trusted proxy CIDR", proxies: []string{"10.0.0.0/8", "192.0.2.0/24"}, want: "203.0.113.7"},
+		{name: "no trusted proxies", want: "192.0.2.1"},
+		{name: "untrusted proxy", proxies: []string{"10.0.0.1"}, want: "192.0.2.1"},
+		{name: "invalid entry", proxies: []string{"load-balancer"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewEngine(config.ServerConfig{TrustedProxies: tt.proxies})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewEngine accepted an invalid proxy")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewEngine: %v", err)
+			}
+			var got string
+			r.GET("/", func(c *gin.Context) { got = c.ClientIP() })
+
+			// httptest requests come from 192.0.2.1.
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("# metrics")) })
+	tests := []struct {
+		name        string
+		metrics     http.Handler
+		pprof       bool
+		wantMetrics int
+	}{
+		{name: "metrics only", metrics: metrics, wantMetrics: http.StatusOK},
+		{name: "metrics and pprof", metrics: metrics, pprof: true, wantMetrics: http.StatusOK},
+		{name: "pprof without metrics", pprof: true, wantMetrics: http.StatusNotFound},
+		{name: "neither", wantMetrics: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := AdminHandler(config.ServerConfig{EnablePprof: tt.pprof}, tt.metrics)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			if w.Code != tt.wantMetrics {
+				t.Errorf("GET /metrics = %d, want %d", w.Code, tt.wantMetrics)
+			}
+
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+			profiles := strings.Contains(w.Body.String(), "goroutine")
+			if profiles != tt.pprof {
+				t.Errorf("GET /debug/pprof/ served profiles = %v, want %v", profiles, tt.pprof)
+			}
+		})
+	}
+}
+
+func TestStartAdminServerOnItsOwnPort(t *testing.T) {
+	cfg := config.ServerConfig{Host: "127.0.0.1", Port: freePort(t), MetricsPort: freePort(t)}
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("# metrics")) })
+	srv, err := StartAdminServer(cfg, metrics)
+	if err != nil {
+		t.Fatalf("StartAdminServer: %v", err)
+	}
+	defer srv.Close()
+
+	// The port is bound once StartAdminServer returns.
+	resp, err := http.Get("http://" + net.JoinHostPort(cfg.Host, cfg.MetricsPort) + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "# metrics" {
+		t.Errorf("GET /metrics = %d %q, want the metrics", resp.StatusCode, body)
+	}
+	if conn, err := net.Dial("tcp", net.JoinHostPort(cfg.Host, cfg.Port)); err == nil {
+		conn.Close()
+		t.Error("admin server also listens on the API port")
+	}
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartAdminServerReportsListenErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	srv, err := StartAdminServer(config.ServerConfig{Host: "127.0.0.1", MetricsPort: port}, http.NotFoundHandler())
+	if err == nil || !strings.Contains(err.Error(), "admin server: listen tcp") {
+		t.Errorf("StartAdminServer = %v, %v, want a listen error", srv, err)
+	}
+}
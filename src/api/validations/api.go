@@ -1,18 +1,150 @@
 package api
 
 import (
+	"automart/api/middlewares"
 	"automart/api/routers"
+	"automart/api/server"
 	"automart/config"
+	"automart/data"
+	"automart/data/cache"
+	"automart/data/db"
+	"automart/pkg/health"
+	"automart/pkg/lifecycle"
+	"automart/pkg/logging"
+	"automart/pkg/metrics"
+	"automart/pkg/tracing"
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-func InitServer() {
-	cfg := config.GetConfig()
-	r := gin.New()
-	r.Use(gin.Logger(), gin.Recovery())
+// InitServer connects the backing services for the Config supplied by
+// loader, serves the API until a shutdown signal arrives and then closes
+// everything down through a lifecycle.Lifecycle, newest resource first. It
+// terminates the process on any error.
+func InitServer(loader config.Loader) {
+	cfg, err := loader.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+	lc := lifecycle.NewLifecycle(cfg.Server)
+
+	loggers, err := logging.NewLoggerManager(cfg.Logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger := loggers.Root()
+	defer logger.Sync()
+
+	database, err := db.NewGormDBWithReplica(ctx, cfg.Postgres, cfg.PostgresReplica, loggers.Named("gorm"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	appCache, err := cache.NewCache(ctx, cfg.Redis)
+	if err != nil {
+		_ = data.CloseAll(database, nil)
+		log.Fatal(err)
+	}
+	// rdb is nil when Redis is optional and unavailable.
+	rdb := cache.RedisClient(appCache)
+	lc.Add("postgres and redis", func(context.Context) error { return data.CloseAll(database, rdb) })
+
+	shutdownTracer, err := tracing.InitTracer(cfg.Tracing)
+	if err != nil {
+		_ = lc.Shutdown()
+		log.Fatal(err)
+	}
+	lc.Add("tracer", shutdownTracer)
+
+	server.ApplyGinMode(cfg.Server)
+	r, err := server.NewEngine(cfg.Server)
+	if err != nil {
+		_ = lc.Shutdown()
+		log.Fatal(err)
+	}
+	skipLogging := []string{"/api/v1/health", "/api/v2/health", cfg.Health.LivenessPath, cfg.Health.ReadinessPath}
+	if cfg.Metrics.Enabled && cfg.Server.MetricsPort == "" {
+		skipLogging = append(skipLogging, cfg.Metrics.Path)
+	}
+	r.Use(middlewares.RequestID(), middlewares.RequestLogger(logger, cfg.Server.SlowRequestThreshold, skipLogging...), middlewares.Recovery(logger))
+	r.Use(middlewares.NewCorsMiddleware(cfg.Cors))
+	r.Use(middlewares.MaxBodySize(cfg.Server.MaxRequestBodyBytes))
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// serve runs a server in the background; if it stops for any reason
+	// other than the shutdown, its error is reported and the process stops.
+	var serving sync.WaitGroup
+	serveErrs := make(chan error, 1)
+	serve := func(name string, run func() error) {
+		serving.Add(1)
+		go func() {
+			defer serving.Done()
+			if err := run(); !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- fmt.Errorf("%s: %w", name, err)
+				cancel()
+			}
+		}()
+	}
+
+	registry, metricsHandler := metrics.NewMetricsRegistry(cfg.Metrics)
+	if cfg.Server.MetricsPort != "" {
+		if cfg.Metrics.Enabled || cfg.Server.EnablePprof {
+			var adminMetrics http.Handler
+			if cfg.Metrics.Enabled {
+				adminMetrics = metricsHandler
+			}
+			admin, err := server.StartAdminServer(cfg.Server, adminMetrics)
+			if err != nil {
+				_ = lc.Shutdown()
+				log.Fatal(err)
+			}
+			lc.Add("admin server", admin.Shutdown)
+		}
+	} else {
+		if cfg.Metrics.Enabled {
+			r.GET(cfg.Metrics.Path, gin.WrapH(metricsHandler))
+		}
+		if cfg.Server.EnablePprof {
+			r.GET("/debug/pprof/*profile", gin.WrapH(server.AdminHandler(cfg.Server, http.NotFoundHandler())))
+		}
+	}
+	if cfg.Metrics.Enabled {
+		namespaced := prometheus.WrapRegistererWithPrefix(cfg.Metrics.Namespace+"_", registry)
+		stopDBStats, err := metrics.RegisterDBStats(namespaced, database, cfg.Metrics.StatsInterval)
+		if err != nil {
+			_ = lc.Shutdown()
+			log.Fatal(err)
+		}
+		lc.Add("postgres stats", stopping(stopDBStats))
+		if rdb != nil {
+			stopRedisStats, err := metrics.RegisterRedisStats(namespaced, rdb, cfg.Metrics.StatsInterval)
+			if err != nil {
+				_ = lc.Shutdown()
+				log.Fatal(err)
+			}
+			lc.Add("redis stats", stopping(stopRedisStats))
+		}
+	}
+
+	readinessChecks := []func(context.Context) error{health.Postgres(database)}
+	if rdb != nil {
+		readinessChecks = append(readinessChecks, health.Redis(rdb))
+	}
+	r.GET(cfg.Health.LivenessPath, gin.WrapF(health.LivenessHandler()))
+	r.GET(cfg.Health.ReadinessPath, gin.WrapF(health.ReadinessHandler(cfg.Health.ReadinessTimeout, readinessChecks...)))
+
+	routers.RegisterDocs(r, cfg.Docs)
+	routers.RegisterStatic(r, cfg.Static)
+
 	api := r.Group("/api")
+	api.Use(middlewares.NewRateLimiter(cfg.RateLimit, rdb))
 
 	v1 := api.Group("/v1")
 	{
@@ -20,6 +152,7 @@ func InitServer() {
 		testRouter := v1.Group("test")
 		routers.Health(health)
 		routers.TestRouter(testRouter)
+		v1.GET("/debug/config", gin.WrapF(config.ConfigHandler(cfg)))
 	}
 
 	v2 := api.Group("/v2")
@@ -28,5 +161,30 @@ func InitServer() {
 		routers.Health(health)
 	}
 
-	r.Run(fmt.Sprintf(":%s", cfg.Server.Port))
+	srv := server.NewHTTPServer(cfg.Server, r)
+	serve("serve", func() error { return server.Serve(srv, cfg.Server) })
+	// Added last so in-flight requests drain before anything else is closed.
+	lc.Add("http server", srv.Shutdown)
+
+	shutdownErr := lc.Run(runCtx)
+	serving.Wait()
+	close(serveErrs)
+	var errs []error
+	for err := range serveErrs {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		log.Fatal(err)
+	}
+	if shutdownErr != nil {
+		log.Fatal(shutdownErr)
+	}
+}
+
+// stopping adapts a stop func to a lifecycle closer.
+func stopping(stop func()) func(context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
 }
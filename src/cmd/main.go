@@ -1,7 +1,37 @@
 package main
 
-import api "automart/api/validations"
+import (
+	api "automart/api/validations"
+	"automart/config"
+	"automart/pkg/health"
+	"context"
+	"log"
+	"os"
+
+	"github.com/spf13/pflag"
+)
 
 func main() {
-	api.InitServer()
+	config.RegisterFlags(pflag.CommandLine)
+	checkConfig := pflag.Bool("check-config", false, "load and validate the config, connect to every dependency and exit")
+	pflag.Parse()
+
+	if *checkConfig {
+		os.Exit(selfCheck())
+	}
+	api.InitServer(config.FileLoader{})
+}
+
+// selfCheck runs health.SelfCheck on the loaded config and returns the exit code.
+func selfCheck() int {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Printf("self-check: %v", err)
+		return 1
+	}
+	if err := health.SelfCheck(context.Background(), cfg); err != nil {
+		return 1
+	}
+	log.Printf("self-check: all checks passed")
+	return 0
 }
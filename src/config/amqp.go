@@ -0,0 +1,27 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// validate: Checks that an enabled broker has an amqp:// or amqps:// URL with
+// a host, an exchange and a non-negative publish timeout.
+
+func (a AMQPConfig) validate() []error {
+	if !a.Enabled {
+		return nil
+	}
+	var errs []error
+	if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") || u.Host == "" {
+		errs = append(errs, errors.New("invalid AMQP.URL: must be an amqp:// or amqps:// URI with a host"))
+	}
+	if a.Exchange == "" {
+		errs = append(errs, errors.New("AMQP.Exchange is required when AMQP.Enabled is set"))
+	}
+	if a.PublishTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid AMQP.PublishTimeout %s: must not be negative", a.PublishTimeout))
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuthSecretValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		secret  string
+		wantErr string
+	}{
+		{name: "long secret in production", env: "production", secret: strings.Repeat("k", 32)},
+		{name: "missing secret in development", env: "development", secret: "", wantErr: "Auth.SecretKey is required"},
+		{name: "short secret in production", env: "production", secret: "short", wantErr: "secret is too short (5 bytes, need at least 32)"},
+		{name: "short secret in development", env: "development", secret: "short"},
+		{name: "development placeholder in production", env: "production", secret: "automart-development-secret-change-me", wantErr: "secret is a published placeholder"},
+		{name: "test placeholder in production", env: "production", secret: "automart-test-secret-not-for-production-use", wantErr: "secret is a published placeholder"},
+		{name: "placeholder in development", env: "development", secret: "automart-development-secret-change-me"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Env = tt.env
+			cfg.Auth.SecretKey = tt.secret
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import "reflect"
+
+// Clone: Returns a deep copy of c: slices, maps and pointers such as
+// Logger.DisableColor are copied too, so the clone can be changed, e.g. for
+// a test or a derived sub-service, without touching c or racing with readers
+// of the Config returned by Get. Fields are copied by reflection, so new
+// ones are covered automatically. The clone shares c's Viper instance.
+
+func (c *Config) Clone() *Config {
+	clone := *c
+	deepCopyFields(reflect.ValueOf(&clone).Elem())
+	return &clone
+}
+
+// deepCopyFields: Replaces every exported field of the struct v, which
+// starts out as a shallow copy, with a deep copy of its value.
+
+func deepCopyFields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		f := v.Field(i)
+		f.Set(deepCopy(f))
+	}
+}
+
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		deepCopyFields(cp)
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+	default:
+		return v
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloneIsolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		check  func(t *testing.T, orig *Config)
+	}{
+		{
+			name:   "scalar",
+			mutate: func(c *Config) { c.Postgres.Host = "db.clone" },
+			check: func(t *testing.T, orig *Config) {
+				if orig.Postgres.Host != "db.internal" {
+					t.Errorf("Postgres.Host = %q", orig.Postgres.Host)
+				}
+			},
+		},
+		{
+			name:   "slice element",
+			mutate: func(c *Config) { c.Server.TrustedProxies[0] = "192.0.2.1" },
+			check: func(t *testing.T, orig *Config) {
+				if orig.Server.TrustedProxies[0] != "10.0.0.1" {
+					t.Errorf("TrustedProxies = %q", orig.Server.TrustedProxies)
+				}
+			},
+		},
+		{
+			name:   "slice append",
+			mutate: func(c *Config) { c.Cors.AllowedOrigins = append(c.Cors.AllowedOrigins[:1], "https://evil.example") },
+			check: func(t *testing.T, orig *Config) {
+				if !reflect.DeepEqual(orig.Cors.AllowedOrigins, []string{"https://a.example", "https://b.example"}) {
+					t.Errorf("Cors.AllowedOrigins = %q", orig.Cors.AllowedOrigins)
+				}
+			},
+		},
+		{
+			name:   "slice in another section",
+			mutate: func(c *Config) { c.Redis.SentinelAddrs[1] = "s9:26379" },
+			check: func(t *testing.T, orig *Config) {
+				if orig.Redis.SentinelAddrs[1] != "s2:26379" {
+					t.Errorf("Redis.SentinelAddrs = %q", orig.Redis.SentinelAddrs)
+				}
+			},
+		},
+		{
+			name: "map",
+			mutate: func(c *Config) {
+				c.Features["newCheckout"] = false
+				c.Features["added"] = true
+			},
+			check: func(t *testing.T, orig *Config) {
+				if !reflect.DeepEqual(orig.Features, map[string]bool{"newCheckout": true}) {
+					t.Errorf("Features = %v", orig.Features)
+				}
+			},
+		},
+		{
+			name:   "map in a section",
+			mutate: func(c *Config) { delete(c.Logger.Levels, "gorm") },
+			check: func(t *testing.T, orig *Config) {
+				if orig.Logger.Levels["gorm"] != "warn" {
+					t.Errorf("Logger.Levels = %v", orig.Logger.Levels)
+				}
+			},
+		},
+		{
+			name:   "pointer",
+			mutate: func(c *Config) { *c.Logger.DisableColor = false },
+			check: func(t *testing.T, orig *Config) {
+				if !*orig.Logger.DisableColor {
+					t.Error("Logger.DisableColor changed through the clone")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := cloneFixture(t)
+			clone := orig.Clone()
+			tt.mutate(clone)
+			tt.check(t, orig)
+		})
+	}
+}
+
+func TestCloneCopiesEveryValue(t *testing.T) {
+	orig := cloneFixture(t)
+	clone := orig.Clone()
+	if clone == orig {
+		t.Fatal("Clone returned the same pointer")
+	}
+	if changes := orig.Diff(clone); len(changes) != 0 {
+		t.Errorf("clone differs from the original: %q", changes)
+	}
+	if clone.v != orig.v {
+		t.Error("clone does not share the Viper instance")
+	}
+
+	var empty Config
+	if got := empty.Clone(); got.Server.TrustedProxies != nil || got.Features != nil || got.Logger.DisableColor != nil {
+		t.Errorf("nil fields not kept nil: %+v", got)
+	}
+}
+
+func TestDeepCopyNestedContainers(t *testing.T) {
+	type inner struct{ Tags []string }
+	type outer struct {
+		Grid   [][]int
+		Groups map[string][]string
+		Inner  *inner
+		Items  []inner
+	}
+	orig := outer{
+		Grid:   [][]int{{1, 2}, {3}},
+		Groups: map[string][]string{"a": {"x", "y"}},
+		Inner:  &inner{Tags: []string{"t"}},
+		Items:  []inner{{Tags: []string{"i"}}},
+	}
+	clone := deepCopy(reflect.ValueOf(orig)).Interface().(outer)
+	clone.Grid[0][1] = 9
+	clone.Groups["a"][0] = "z"
+	clone.Inner.Tags[0] = "changed"
+	clone.Items[0].Tags[0] = "changed"
+
+	want := outer{
+		Grid:   [][]int{{1, 2}, {3}},
+		Groups: map[string][]string{"a": {"x", "y"}},
+		Inner:  &inner{Tags: []string{"t"}},
+		Items:  []inner{{Tags: []string{"i"}}},
+	}
+	if !reflect.DeepEqual(orig, want) {
+		t.Errorf("original changed through the copy: %+v", orig)
+	}
+}
+
+// cloneFixture returns a Config with every kind of field Clone must copy set.
+func cloneFixture(t *testing.T) *Config {
+	t.Helper()
+	cfg := validConfig(t)
+	disable := true
+	cfg.Server.TrustedProxies = []string{"10.0.0.1", "10.0.0.2"}
+	cfg.Cors.AllowedOrigins = []string{"https://a.example", "https://b.example"}
+	cfg.Redis.SentinelAddrs = []string{"s1:26379", "s2:26379"}
+	cfg.Features = map[string]bool{"newCheckout": true}
+	cfg.Logger.Levels = map[string]string{"gorm": "warn"}
+	cfg.Logger.DisableColor = &disable
+	return cfg
+}
@@ -0,0 +1,588 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// validYAML is the smallest config that passes Validate in every environment.
+const validYAML = `
+server:
+  port: 5005
+auth:
+  secretKey: 0123456789abcdef0123456789abcdef-test
+postgres:
+  host: db.internal
+  port: 5432
+  user: automart
+  password: pg-secret
+  dbName: automart
+  sslMode: disable
+redis:
+  host: cache.internal
+  port: 6379
+  password: redis-secret
+`
+
+// parseYAML parses doc like ParseConfigFromReader, failing the test on error.
+func parseYAML(t *testing.T, doc string) *Config {
+	t.Helper()
+	cfg, err := ParseConfigFromReader(strings.NewReader(doc), "yml")
+	if err != nil {
+		t.Fatalf("ParseConfigFromReader: %v", err)
+	}
+	return cfg
+}
+
+// writeFile writes content to dir/name and returns the path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useConfigDir points GetConfig at a fresh directory for env and returns it.
+func useConfigDir(t *testing.T, env string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", env)
+	t.Setenv("CONFIG_PATH", dir)
+	t.Setenv(configSourceEnv, "")
+	return dir
+}
+
+func TestGetConfigReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		check func(t *testing.T, err error)
+	}{
+		{
+			name: "unparsable yaml",
+			file: "server: [5005\n",
+			check: func(t *testing.T, err error) {
+				var parseErr viper.ConfigParseError
+				if !errors.As(err, &parseErr) {
+					t.Errorf("error %v does not wrap viper.ConfigParseError", err)
+				}
+			},
+		},
+		{
+			name: "invalid values",
+			file: "server:\n  port: 5005\n",
+			check: func(t *testing.T, err error) {
+				if !strings.Contains(err.Error(), "invalid config") {
+					t.Errorf("error %q does not report the invalid config", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useConfigDir(t, "staging")
+			writeFile(t, dir, "config-staging.yml", tt.file)
+
+			cfg, err := GetConfig()
+			if err == nil {
+				t.Fatalf("GetConfig returned %v, want an error", cfg)
+			}
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	dir := useConfigDir(t, "staging")
+	writeFile(t, dir, "config-staging.yml", validYAML)
+
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if cfg.Env != "staging" || cfg.Postgres.Host != "db.internal" {
+		t.Errorf("got Env %q, Postgres.Host %q; want staging, db.internal", cfg.Env, cfg.Postgres.Host)
+	}
+}
+
+func TestGetConfigDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		setup   func(t *testing.T) (configPath, want string)
+		wantErr bool
+	}{
+		{
+			name: "CONFIG_PATH holds the file",
+			env:  "staging",
+			setup: func(t *testing.T) (string, string) {
+				dir := t.TempDir()
+				writeFile(t, dir, "config-staging.yml", validYAML)
+				return dir, dir
+			},
+		},
+		{
+			name: "CONFIG_PATH is the only location tried",
+			env:  "staging",
+			setup: func(t *testing.T) (string, string) {
+				wd := t.TempDir()
+				writeFile(t, wd, "config-staging.yml", validYAML)
+				t.Chdir(wd)
+				return t.TempDir(), ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "config subdirectory of the working directory",
+			env:  "staging",
+			setup: func(t *testing.T) (string, string) {
+				wd := t.TempDir()
+				dir := filepath.Join(wd, "config")
+				if err := os.Mkdir(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				writeFile(t, dir, "config-staging.yml", validYAML)
+				t.Chdir(wd)
+				return "", dir
+			},
+		},
+		{
+			name: "source directory in development",
+			env:  "development",
+			setup: func(t *testing.T) (string, string) {
+				src, err := os.Getwd()
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Chdir(t.TempDir())
+				return "", src
+			},
+		},
+		{
+			name: "no source directory outside development",
+			env:  "production",
+			setup: func(t *testing.T) (string, string) {
+				t.Chdir(t.TempDir())
+				return "", ""
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath, want := tt.setup(t)
+			t.Setenv("CONFIG_PATH", configPath)
+			dir, err := getConfigDir(getConfigFileName(tt.env), tt.env)
+			if tt.wantErr {
+				if !errors.Is(err, ErrConfigNotFound) {
+					t.Errorf("getConfigDir = %q, %v; want ErrConfigNotFound", dir, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getConfigDir: %v", err)
+			}
+			if dir != want {
+				t.Errorf("getConfigDir = %q, want %q", dir, want)
+			}
+		})
+	}
+}
+
+// loadDir loads config-staging from dir through LoadConfig and ParsConfig.
+func loadDir(t *testing.T, dir string) *Config {
+	t.Helper()
+	v, err := LoadConfig("config-staging", "yml", dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	cfg, err := ParsConfig(v)
+	if err != nil {
+		t.Fatalf("ParsConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestEnvOverridesNestedKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		got   func(c *Config) interface{}
+		want  interface{}
+	}{
+		{name: "key in the file", key: "AUTOMART_POSTGRES_HOST", value: "db.override",
+			got: func(c *Config) interface{} { return c.Postgres.Host }, want: "db.override"},
+		{name: "key only in defaults", key: "AUTOMART_REDIS_POOLSIZE", value: "42",
+			got: func(c *Config) interface{} { return c.Redis.PoolSize }, want: 42},
+		{name: "key in neither", key: "AUTOMART_SERVER_HOST", value: "127.0.0.1",
+			got: func(c *Config) interface{} { return c.Server.Host }, want: "127.0.0.1"},
+		{name: "unprefixed variable is ignored", key: "POSTGRES_HOST", value: "db.override",
+			got: func(c *Config) interface{} { return c.Postgres.Host }, want: "db.internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, "config-staging.yml", validYAML)
+			t.Setenv(tt.key, tt.value)
+
+			if got := tt.got(loadDir(t, dir)); got != tt.want {
+				t.Errorf("%s=%s gave %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDotEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		dotenv    string
+		osEnv     string
+		wantHost  string
+		wantRedis string
+	}{
+		{name: "no .env file", wantHost: "db.internal", wantRedis: "redis-secret"},
+		{
+			name:      ".env values reach the config",
+			dotenv:    "AUTOMART_POSTGRES_HOST=db.dotenv\nAUTOMART_REDIS_PASSWORD=dotenv-secret\n",
+			wantHost:  "db.dotenv",
+			wantRedis: "dotenv-secret",
+		},
+		{
+			name:      "OS environment wins",
+			dotenv:    "AUTOMART_POSTGRES_HOST=db.dotenv\nAUTOMART_REDIS_PASSWORD=dotenv-secret\n",
+			osEnv:     "db.os",
+			wantHost:  "db.os",
+			wantRedis: "dotenv-secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, "config-staging.yml", validYAML)
+			if tt.dotenv != "" {
+				writeFile(t, dir, ".env", tt.dotenv)
+			}
+			// loadDotEnv exports into the process environment; undo it.
+			for _, key := range []string{"AUTOMART_POSTGRES_HOST", "AUTOMART_REDIS_PASSWORD"} {
+				t.Cleanup(func() { _ = os.Unsetenv(key) })
+			}
+			if tt.osEnv != "" {
+				t.Setenv("AUTOMART_POSTGRES_HOST", tt.osEnv)
+			}
+
+			cfg := loadDir(t, dir)
+			if cfg.Postgres.Host != tt.wantHost || cfg.Redis.Password != tt.wantRedis {
+				t.Errorf("got Postgres.Host %q, Redis.Password %q; want %q, %q",
+					cfg.Postgres.Host, cfg.Redis.Password, tt.wantHost, tt.wantRedis)
+			}
+		})
+	}
+}
+
+func TestLoadConfigNotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "empty directory"},
+		{name: "other environment only", files: map[string]string{"config-production.yml": validYAML}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+
+			_, err := LoadConfig("config-staging", "yml", dir)
+			if !errors.Is(err, ErrConfigNotFound) {
+				t.Fatalf("LoadConfig error = %v, want ErrConfigNotFound", err)
+			}
+			if !strings.Contains(err.Error(), dir) {
+				t.Errorf("error %q does not name %s", err, dir)
+			}
+		})
+	}
+}
+
+func TestDefaultsApplyOnlyWhenAbsent(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		want func(c *Config) bool
+	}{
+		{
+			name: "defaults fill omitted keys",
+			want: func(c *Config) bool {
+				return c.Postgres.MaxOpenConns == 25 && c.Postgres.MaxIdleConns == 5 &&
+					c.Postgres.ConnMaxLifetime == 5*time.Minute && c.Redis.PoolSize == 10 &&
+					c.Server.RunMode == "debug"
+			},
+		},
+		{
+			name: "file overrides defaults",
+			yaml: "postgres:\n  maxOpenConns: 50\n  connMaxLifetime: 1m\nserver:\n  runMode: release\n",
+			want: func(c *Config) bool {
+				return c.Postgres.MaxOpenConns == 50 && c.Postgres.ConnMaxLifetime == time.Minute &&
+					c.Server.RunMode == "release" && c.Postgres.MaxIdleConns == 5
+			},
+		},
+		{
+			name: "env overrides defaults",
+			env:  map[string]string{"AUTOMART_REDIS_POOLSIZE": "3", "AUTOMART_POSTGRES_MAXIDLECONNS": "1"},
+			want: func(c *Config) bool {
+				return c.Redis.PoolSize == 3 && c.Postgres.MaxIdleConns == 1 && c.Postgres.MaxOpenConns == 25
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := parseYAML(t, tt.yaml)
+			if !tt.want(cfg) {
+				t.Errorf("unexpected values: Postgres %+v, Redis.PoolSize %d, Server.RunMode %q",
+					cfg.Postgres, cfg.Redis.PoolSize, cfg.Server.RunMode)
+			}
+		})
+	}
+}
+
+func TestParseConfigFromReader(t *testing.T) {
+	cfg := parseYAML(t, validYAML)
+	got := []struct {
+		field     string
+		got, want interface{}
+	}{
+		{"Server.Port", cfg.Server.Port, "5005"},
+		{"Postgres.Host", cfg.Postgres.Host, "db.internal"},
+		{"Postgres.Port", cfg.Postgres.Port, "5432"},
+		{"Postgres.User", cfg.Postgres.User, "automart"},
+		{"Postgres.Password", cfg.Postgres.Password, "pg-secret"},
+		{"Postgres.DbName", cfg.Postgres.DbName, "automart"},
+		{"Postgres.SSLMode", cfg.Postgres.SSLMode, "disable"},
+		{"Redis.Host", cfg.Redis.Host, "cache.internal"},
+		{"Redis.Port", cfg.Redis.Port, "6379"},
+		{"Redis.Password", cfg.Redis.Password, "redis-secret"},
+	}
+	for _, g := range got {
+		if g.got != g.want {
+			t.Errorf("%s = %v, want %v", g.field, g.got, g.want)
+		}
+	}
+
+	for _, doc := range []string{"server: [5005\n", "\tserver: 5005\n"} {
+		if _, err := ParseConfigFromReader(strings.NewReader(doc), "yml"); err == nil {
+			t.Errorf("ParseConfigFromReader(%q) succeeded, want a parse error", doc)
+		}
+	}
+}
+
+func TestGetConfigFileName(t *testing.T) {
+	tests := []struct {
+		env, want string
+	}{
+		{env: "", want: "config-development"},
+		{env: "staging", want: "config-staging"},
+		{env: "test", want: "config-test"},
+		{env: "Production", want: "config-production"},
+		{env: "../../etc/passwd", want: "config-etcpasswd"},
+		{env: "../", want: "config-development"},
+		{env: "qa_2-eu", want: "config-qa_2-eu"},
+	}
+	for _, tt := range tests {
+		if got := getConfigFileName(tt.env); got != tt.want {
+			t.Errorf("getConfigFileName(%q) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigMergesBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		env      string
+		wantHost string
+		wantUser string
+	}{
+		{name: "no base file", env: validYAML, wantHost: "db.internal", wantUser: "automart"},
+		{
+			name:     "env file overrides base",
+			base:     validYAML,
+			env:      "postgres:\n  host: db.staging\n",
+			wantHost: "db.staging",
+			wantUser: "automart",
+		},
+		{
+			name:     "base only keys survive",
+			base:     "postgres:\n  user: base-user\n",
+			env:      strings.Replace(validYAML, "  user: automart\n", "", 1),
+			wantHost: "db.internal",
+			wantUser: "base-user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.base != "" {
+				writeFile(t, dir, "config-base.yml", tt.base)
+			}
+			writeFile(t, dir, "config-staging.yml", tt.env)
+
+			cfg := loadDir(t, dir)
+			if cfg.Postgres.Host != tt.wantHost || cfg.Postgres.User != tt.wantUser {
+				t.Errorf("got Postgres.Host %q, User %q; want %q, %q", cfg.Postgres.Host, cfg.Postgres.User, tt.wantHost, tt.wantUser)
+			}
+			if cfg.Postgres.DbName != "automart" {
+				t.Errorf("Postgres.DbName = %q, want automart", cfg.Postgres.DbName)
+			}
+		})
+	}
+}
+
+func TestGetConfigEmbeddedFallback(t *testing.T) {
+	tests := []struct {
+		env      string
+		fallback bool
+	}{
+		{env: "development", fallback: true},
+		{env: "test", fallback: true},
+		{env: "staging"},
+		{env: "production"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			useConfigDir(t, tt.env) // empty: no config file on disk
+
+			cfg, err := GetConfig()
+			if !tt.fallback {
+				if !errors.Is(err, ErrConfigNotFound) {
+					t.Fatalf("GetConfig = %v, %v; want ErrConfigNotFound", cfg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetConfig: %v", err)
+			}
+			if cfg.Env != tt.env || cfg.Server.Port == "" || cfg.Postgres.Host == "" {
+				t.Errorf("embedded defaults not loaded: Env %q, Server.Port %q, Postgres.Host %q", cfg.Env, cfg.Server.Port, cfg.Postgres.Host)
+			}
+		})
+	}
+}
+
+// setRequiredEnv sets the AUTOMART_* variables matching validYAML.
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	for k, v := range map[string]string{
+		"AUTOMART_SERVER_PORT":     "5005",
+		"AUTOMART_AUTH_SECRETKEY":  "0123456789abcdef0123456789abcdef-test",
+		"AUTOMART_POSTGRES_HOST":   "db.internal",
+		"AUTOMART_POSTGRES_PORT":   "5432",
+		"AUTOMART_POSTGRES_USER":   "automart",
+		"AUTOMART_POSTGRES_DBNAME": "automart",
+		"AUTOMART_REDIS_HOST":      "cache.internal",
+		"AUTOMART_REDIS_PORT":      "6379",
+	} {
+		t.Setenv(k, v)
+	}
+}
+
+func TestGetConfigFromEnvOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		unset   string
+		wantErr string
+	}{
+		{name: "all required fields set"},
+		{name: "missing postgres host", unset: "AUTOMART_POSTGRES_HOST", wantErr: "missing required config fields: Postgres.Host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A config file that must not be read.
+			dir := useConfigDir(t, "staging")
+			writeFile(t, dir, "config-staging.yml", strings.Replace(validYAML, "db.internal", "db.from-file", 1))
+			t.Setenv(configSourceEnv, "env")
+			setRequiredEnv(t)
+			if tt.unset != "" {
+				t.Setenv(tt.unset, "")
+			}
+
+			cfg, err := GetConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetConfig error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetConfig: %v", err)
+			}
+			if cfg.Postgres.Host != "db.internal" || cfg.Server.Port != "5005" || cfg.Redis.Addr() != "cache.internal:6379" {
+				t.Errorf("got Postgres.Host %q, Server.Port %q, Redis %s", cfg.Postgres.Host, cfg.Server.Port, cfg.Redis.Addr())
+			}
+			if cfg.Postgres.MaxOpenConns != 25 {
+				t.Errorf("Postgres.MaxOpenConns = %d, want the default 25", cfg.Postgres.MaxOpenConns)
+			}
+		})
+	}
+}
+
+func TestDocsDefaultsFollowEnv(t *testing.T) {
+	tests := []struct {
+		appEnv string
+		yaml   string
+		want   bool
+	}{
+		{appEnv: "development", want: true},
+		{appEnv: "staging", want: true},
+		{appEnv: "production", want: false},
+		{appEnv: "production", yaml: "docs:\n  enabled: true\n", want: true},
+		{appEnv: "development", yaml: "docs:\n  enabled: false\n", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.appEnv+"/"+tt.yaml, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			cfg := parseYAML(t, tt.yaml)
+			if cfg.Docs.Enabled != tt.want {
+				t.Errorf("Docs.Enabled = %v, want %v", cfg.Docs.Enabled, tt.want)
+			}
+			if cfg.Docs.Path != "/swagger" || cfg.Docs.Title != "AutoMart API" {
+				t.Errorf("Docs = %+v, want the default path and title", cfg.Docs)
+			}
+		})
+	}
+}
+
+func TestLoggerDisableColorDecoding(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{yaml: "", want: "<nil>"},
+		{yaml: "logger:\n  disableColor: true\n", want: "true"},
+		{yaml: "logger:\n  disableColor: false\n", want: "false"},
+	}
+	for _, tt := range tests {
+		cfg := parseYAML(t, tt.yaml)
+		got := "<nil>"
+		if cfg.Logger.DisableColor != nil {
+			got = strconv.FormatBool(*cfg.Logger.DisableColor)
+		}
+		if got != tt.want {
+			t.Errorf("DisableColor for %q = %s, want %s", tt.yaml, got, tt.want)
+		}
+	}
+}
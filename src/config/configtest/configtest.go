@@ -0,0 +1,56 @@
+package configtest
+
+import (
+	"automart/config"
+	"fmt"
+	"strings"
+)
+
+// testConfig points at the Postgres and Redis started for integration tests
+// (e.g. by docker compose or testcontainers) on their default ports.
+const testConfig = `
+server:
+  port: 5005
+  runMode: test
+logger:
+  encoding: console
+  level: warn
+auth:
+  secretKey: automart-test-secret-not-for-production-use
+postgres:
+  host: localhost
+  port: 5432
+  user: postgres
+  password: postgres
+  dbName: automart_test
+  sslMode: disable
+  connectRetries: 0
+redis:
+  host: localhost
+  port: 6379
+  db: 1
+  connectRetries: 0
+migration:
+  autoMigrate: true
+`
+
+// NewTestConfig returns a valid Config for the "test" environment with the
+// settings above, the built-in defaults for everything else and AUTOMART_*
+// environment overrides applied, so CI can point it at its own containers.
+// The overrides then run in order and may change any field; the result is
+// not validated again, so a test can also build an invalid Config on
+// purpose. It panics if the baseline itself does not load or validate.
+func NewTestConfig(overrides ...func(*config.Config)) *config.Config {
+	cfg, err := config.ParseConfigFromReader(strings.NewReader(testConfig), "yml")
+	if err != nil {
+		panic(fmt.Sprintf("configtest: %v", err))
+	}
+	cfg.Env = "test"
+	if err := cfg.Validate(); err != nil {
+		panic(fmt.Sprintf("configtest: baseline config is invalid: %v", err))
+	}
+	for _, override := range overrides {
+		override(cfg)
+	}
+	return cfg
+}
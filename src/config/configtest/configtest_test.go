@@ -0,0 +1,85 @@
+package configtest
+
+import (
+	"automart/config"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewTestConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   []func(*config.Config)
+		wantChanged []string
+		check       func(t *testing.T, c *config.Config)
+	}{
+		{name: "baseline"},
+		{
+			name:        "override changes only the Postgres host",
+			overrides:   []func(*config.Config){func(c *config.Config) { c.Postgres.Host = "pg.ci" }},
+			wantChanged: []string{"Postgres.Host: localhost→pg.ci"},
+			check: func(t *testing.T, c *config.Config) {
+				if c.Postgres.Host != "pg.ci" {
+					t.Errorf("Postgres.Host = %q, want pg.ci", c.Postgres.Host)
+				}
+			},
+		},
+		{
+			name: "overrides run in order",
+			overrides: []func(*config.Config){
+				func(c *config.Config) { c.Redis.Host = "first" },
+				func(c *config.Config) { c.Redis.Host = "second" },
+			},
+			wantChanged: []string{"Redis.Host: localhost→second"},
+			check: func(t *testing.T, c *config.Config) {
+				if c.Redis.Host != "second" {
+					t.Errorf("Redis.Host = %q, want the last override", c.Redis.Host)
+				}
+			},
+		},
+		{
+			name:        "invalid result is not rejected",
+			overrides:   []func(*config.Config){func(c *config.Config) { c.Postgres.Port = "0" }},
+			wantChanged: []string{"Postgres.Port: 5432→0"},
+			check: func(t *testing.T, c *config.Config) {
+				if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "Postgres.Port") {
+					t.Errorf("Validate() = %v, want a Postgres.Port error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := NewTestConfig()
+			cfg := NewTestConfig(tt.overrides...)
+			if got := baseline.Diff(cfg); !slices.Equal(got, tt.wantChanged) {
+				t.Errorf("changed fields = %q, want %q", got, tt.wantChanged)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestNewTestConfigBaseline(t *testing.T) {
+	cfg := NewTestConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("baseline does not validate: %v", err)
+	}
+	if cfg.Env != "test" || cfg.Server.RunMode != "test" || cfg.Postgres.DbName != "automart_test" || cfg.Redis.Db != 1 {
+		t.Errorf("Env %q, RunMode %q, DbName %q, Redis.Db %d; want the test settings",
+			cfg.Env, cfg.Server.RunMode, cfg.Postgres.DbName, cfg.Redis.Db)
+	}
+	if cfg.Postgres.ConnectRetries != 0 || cfg.Redis.ConnectRetries != 0 {
+		t.Error("connect retries are enabled, so a missing container would stall tests")
+	}
+}
+
+func TestNewTestConfigEnvOverrides(t *testing.T) {
+	t.Setenv("AUTOMART_POSTGRES_HOST", "pg.container")
+	if got := NewTestConfig().Postgres.Host; got != "pg.container" {
+		t.Errorf("Postgres.Host = %q, want the AUTOMART_POSTGRES_HOST value", got)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// decodeHook: The hooks ParsConfig decodes with: durationHook, plus viper's
+// default splitting of comma-separated strings into slices.
+
+func decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		mapstructure.StringToSliceHookFunc(","),
+	))
+}
+
+// durationHook: Decodes time.Duration fields from a Go duration string such
+// as "300ms", "30s" or "5m", or from a bare number of seconds, written as
+// 300 in YAML or "300" in an environment variable. Without it a bare number
+// would be read as nanoseconds, or rejected when it comes from the
+// environment. Values that already are durations, such as the defaults, pass
+// through unchanged.
+
+func durationHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != durationType || from == durationType {
+		return data, nil
+	}
+	switch from.Kind() {
+	case reflect.String:
+		s := strings.TrimSpace(data.(string))
+		if s == "" {
+			return time.Duration(0), nil
+		}
+		if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Duration(secs) * time.Second, nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: use a unit such as \"300ms\", \"30s\" or \"5m\", or a whole number of seconds", s)
+		}
+		return d, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(reflect.ValueOf(data).Int()) * time.Second, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(reflect.ValueOf(data).Uint()) * time.Second, nil
+	case reflect.Float32, reflect.Float64:
+		// JSON, e.g. from a remote provider, has only floats.
+		return time.Duration(reflect.ValueOf(data).Float() * float64(time.Second)), nil
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDurationEnvOverrides(t *testing.T) {
+	fields := []struct {
+		env string
+		get func(c *Config) time.Duration
+	}{
+		{env: "AUTOMART_POSTGRES_CONNMAXLIFETIME", get: func(c *Config) time.Duration { return c.Postgres.ConnMaxLifetime }},
+		{env: "AUTOMART_POSTGRES_CONNMAXIDLETIME", get: func(c *Config) time.Duration { return c.Postgres.ConnMaxIdleTime }},
+		{env: "AUTOMART_SERVER_SHUTDOWNTIMEOUT", get: func(c *Config) time.Duration { return c.Server.ShutdownTimeout }},
+		{env: "AUTOMART_REDIS_DIALTIMEOUT", get: func(c *Config) time.Duration { return c.Redis.DialTimeout }},
+	}
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "5m", want: 5 * time.Minute},
+		{value: "300s", want: 300 * time.Second},
+		{value: "300", want: 300 * time.Second},
+		{value: " 300 ", want: 300 * time.Second},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: "250ms", want: 250 * time.Millisecond},
+		{value: "0", want: 0},
+	}
+	for _, f := range fields {
+		for _, tt := range tests {
+			t.Run(f.env+"="+tt.value, func(t *testing.T) {
+				t.Setenv(f.env, tt.value)
+				cfg := parseYAML(t, validYAML)
+				if got := f.get(cfg); got != tt.want {
+					t.Errorf("%s=%q decoded to %s, want %s", f.env, tt.value, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestDurationFileValues(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want time.Duration
+	}{
+		{yaml: "5m", want: 5 * time.Minute},
+		{yaml: "300s", want: 300 * time.Second},
+		{yaml: "300", want: 300 * time.Second},
+		{yaml: `"300"`, want: 300 * time.Second},
+		{yaml: "1.5", want: 1500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			cfg := parseYAML(t, "postgres:\n  connMaxLifetime: "+tt.yaml+"\n")
+			if cfg.Postgres.ConnMaxLifetime != tt.want {
+				t.Errorf("connMaxLifetime: %s decoded to %s, want %s", tt.yaml, cfg.Postgres.ConnMaxLifetime, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationDefaultsPassThrough(t *testing.T) {
+	cfg := parseYAML(t, "")
+	if cfg.Postgres.ConnMaxLifetime != 5*time.Minute || cfg.Server.ShutdownTimeout != 10*time.Second {
+		t.Errorf("ConnMaxLifetime %s, ShutdownTimeout %s; want the 5m and 10s defaults",
+			cfg.Postgres.ConnMaxLifetime, cfg.Server.ShutdownTimeout)
+	}
+}
+
+func TestDurationRejectsInvalid(t *testing.T) {
+	for _, value := range []string{"five minutes", "5 m", "-"} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("AUTOMART_POSTGRES_CONNMAXLIFETIME", value)
+			_, err := ParseConfigFromReader(strings.NewReader(validYAML), "yml")
+			if err == nil || !strings.Contains(err.Error(), "invalid duration") || !strings.Contains(err.Error(), "whole number of seconds") {
+				t.Errorf("error = %v, want an invalid duration error with the accepted forms", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// setDefaults: Registers fallback values for keys whose Go zero value would
+// misbehave (e.g. MaxOpenConns 0 means unlimited). Defaults have the lowest
+// precedence, so the config file and environment always override them.
+// A few defaults depend on APP_ENV, e.g. the API docs are off in production.
+
+func setDefaults(v *viper.Viper) {
+	production := currentEnv() == "production"
+
+	v.SetDefault("server.runMode", "debug")
+	v.SetDefault("server.shutdownTimeout", 10*time.Second)
+
+	v.SetDefault("postgres.maxOpenConns", 25)
+	v.SetDefault("postgres.maxIdleConns", 5)
+	v.SetDefault("postgres.connMaxLifetime", 5*time.Minute)
+	v.SetDefault("postgres.connectRetries", 5)
+	v.SetDefault("postgres.connectRetryDelay", time.Second)
+	v.SetDefault("postgres.connectTimeout", 5*time.Second)
+	v.SetDefault("postgres.slowQueryThreshold", 200*time.Millisecond)
+
+	v.SetDefault("auth.accessTokenExpiry", 15*time.Minute)
+	v.SetDefault("auth.refreshTokenExpiry", 7*24*time.Hour)
+	v.SetDefault("auth.issuer", "automart")
+
+	v.SetDefault("mail.port", "587")
+
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.namespace", "automart")
+	v.SetDefault("metrics.statsInterval", 15*time.Second)
+
+	v.SetDefault("tracing.serviceName", "automart")
+	v.SetDefault("tracing.sampleRatio", 1.0)
+
+	v.SetDefault("httpClient.timeout", 30*time.Second)
+
+	v.SetDefault("docs.enabled", !production)
+	v.SetDefault("docs.path", "/swagger")
+	v.SetDefault("docs.title", "AutoMart API")
+
+	v.SetDefault("static.urlPrefix", "/static")
+
+	v.SetDefault("i18n.defaultLocale", "en")
+	v.SetDefault("i18n.supportedLocales", []string{"en", "fa"})
+
+	v.SetDefault("pricing.currency", "USD")
+	v.SetDefault("pricing.minorUnits", 2)
+	v.SetDefault("pricing.symbol", "$")
+
+	v.SetDefault("image.maxWidth", 1920)
+	v.SetDefault("image.maxHeight", 1080)
+	v.SetDefault("image.jpegQuality", 85)
+	v.SetDefault("image.allowedFormats", []string{"jpeg", "png"})
+	v.SetDefault("image.maxBytes", 20<<20)
+	v.SetDefault("image.maxPixels", 50_000_000)
+
+	v.SetDefault("search.indexPrefix", "automart_")
+
+	v.SetDefault("amqp.exchange", "automart.events")
+	v.SetDefault("amqp.publishTimeout", 5*time.Second)
+
+	v.SetDefault("health.livenessPath", "/healthz")
+	v.SetDefault("health.readinessPath", "/readyz")
+	v.SetDefault("health.readinessTimeout", 2*time.Second)
+
+	v.SetDefault("pagination.defaultPageSize", 20)
+	v.SetDefault("pagination.maxPageSize", 100)
+
+	v.SetDefault("redis.mode", RedisModeSingle)
+	v.SetDefault("redis.poolSize", 10)
+	v.SetDefault("redis.dialTimeout", defaultRedisDialTimeout)
+	v.SetDefault("redis.readTimeout", defaultRedisReadTimeout)
+	v.SetDefault("redis.writeTimeout", defaultRedisWriteTimeout)
+	v.SetDefault("redis.poolTimeout", defaultRedisPoolTimeout)
+	v.SetDefault("redis.idleCheckFrequency", defaultRedisIdleCheckFrequency)
+	v.SetDefault("redis.connectRetries", 3)
+	v.SetDefault("redis.connectRetryDelay", time.Second)
+}
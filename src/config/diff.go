@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Diff: Lists the fields that differ between c and other as
+// "Section.Field: old→new", in struct order. Secret fields are reported as
+// "****→****", and URL passwords as "xxxxx", so the result is safe to log.
+// Fields are found by reflection, so new ones are covered automatically.
+
+func (c *Config) Diff(other *Config) []string {
+	return diffStruct(reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem(), "")
+}
+
+func diffStruct(a, b reflect.Value, prefix string) []string {
+	var changes []string
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := prefix + f.Name
+		x, y := a.Field(i), b.Field(i)
+		switch {
+		case x.Kind() == reflect.Struct:
+			changes = append(changes, diffStruct(x, y, name+".")...)
+		case reflect.DeepEqual(x.Interface(), y.Interface()), bothEmpty(x, y):
+		case x.Kind() == reflect.String && isSecretField(f.Name):
+			changes = append(changes, fmt.Sprintf("%s: %s→%s", name, redactedValue, redactedValue))
+		case x.Kind() == reflect.String:
+			changes = append(changes, fmt.Sprintf("%s: %s→%s", name, redactURL(x.String()), redactURL(y.String())))
+		case x.Type() == reflect.TypeOf([]string(nil)):
+			changes = append(changes, fmt.Sprintf("%s: %v→%v", name, redactURLs(x), redactURLs(y)))
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %v→%v", name, display(x), display(y)))
+		}
+	}
+	return changes
+}
+
+// redactURLs: Returns the elements of the []string v with URL passwords
+// redacted.
+
+func redactURLs(v reflect.Value) []string {
+	out := make([]string, v.Len())
+	for i := range out {
+		out[i] = redactURL(v.Index(i).String())
+	}
+	return out
+}
+
+// bothEmpty: Treats nil and empty slices or maps as equal, as viper does not
+// distinguish them.
+
+func bothEmpty(x, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.Slice, reflect.Map:
+		return x.Len() == 0 && y.Len() == 0
+	}
+	return false
+}
+
+// display: Returns the value to print for v, following pointers so that
+// optional fields show their value, or <unset> when nil.
+
+func display(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "<unset>"
+		}
+		return v.Elem().Interface()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/subosito/gotenv"
+)
+
+// envPrefix namespaces environment overrides, e.g. AUTOMART_POSTGRES_HOST
+// overrides Postgres.Host.
+const envPrefix = "AUTOMART"
+
+// configKeys: Returns the dotted, lower-cased viper key of every leaf field
+// in the Config struct, e.g. "postgres.host".
+
+func configKeys() []string {
+	return structKeys(reflect.TypeOf(Config{}), "")
+}
+
+func structKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		key := prefix + strings.ToLower(f.Name)
+		if f.Type.Kind() == reflect.Struct {
+			keys = append(keys, structKeys(f.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// bindEnvs: Binds every config key to its environment variable. AutomaticEnv
+// alone only applies to keys viper already knows about, so keys that are
+// absent from the YAML file would otherwise never be overridden on Unmarshal.
+//
+// With every key bound, a value is taken from the first of these sources
+// that sets it, whether or not the key also has a default:
+//
+//  1. a secret file named by AUTOMART_<KEY>_FILE (applySecretFiles)
+//  2. a command line flag (BindFlags)
+//  3. the AUTOMART_<KEY> environment variable, including values from .env
+//  4. the config file, merged over config-base and any extends chain
+//  5. the default registered in setDefaults
+//
+// An empty environment variable counts as unset. Map fields (Features,
+// Logger.Levels) are bound as a whole, so their entries cannot be
+// overridden one by one from the environment.
+
+func bindEnvs(v *viper.Viper) {
+	for _, key := range configKeys() {
+		_ = v.BindEnv(key)
+	}
+}
+
+// loadDotEnv: Exports the variables of configPath/.env into the process
+// environment so they feed AutomaticEnv. A missing file is not an error, and
+// variables already set in the real environment are never overridden.
+
+func loadDotEnv(configPath string) error {
+	path := filepath.Join(configPath, ".env")
+	if !fileExists(path) {
+		return nil
+	}
+	if err := gotenv.Load(path); err != nil {
+		return fmt.Errorf("load %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// Config value sources, from lowest to highest precedence.
+const (
+	fromDefault = iota
+	fromFile
+	fromEnv
+	fromFlag
+	fromSecretFile
+)
+
+var sourceNames = []string{"default", "file", "env", "flag", "_FILE"}
+
+func TestValuePrecedence(t *testing.T) {
+	tests := []struct {
+		key    string
+		get    func(c *Config) string
+		values map[int]string // value each source sets; a missing source is not tried
+	}{
+		{
+			key:    "postgres.maxOpenConns",
+			get:    func(c *Config) string { return strconv.Itoa(c.Postgres.MaxOpenConns) },
+			values: map[int]string{fromDefault: "25", fromFile: "30", fromEnv: "40"},
+		},
+		{
+			key:    "server.shutdownTimeout",
+			get:    func(c *Config) string { return c.Server.ShutdownTimeout.String() },
+			values: map[int]string{fromDefault: "10s", fromFile: "20s", fromEnv: "30s"},
+		},
+		{
+			key:    "server.runMode",
+			get:    func(c *Config) string { return c.Server.RunMode },
+			values: map[int]string{fromDefault: "debug", fromFile: "release", fromEnv: "test", fromFlag: "flag"},
+		},
+		{
+			key:    "postgres.host",
+			get:    func(c *Config) string { return c.Postgres.Host },
+			values: map[int]string{fromDefault: "", fromFile: "db.file", fromEnv: "db.env", fromFlag: "db.flag"},
+		},
+		{
+			key:    "postgres.password",
+			get:    func(c *Config) string { return c.Postgres.Password },
+			values: map[int]string{fromDefault: "", fromFile: "pw-file", fromEnv: "pw-env", fromFlag: "pw-flag", fromSecretFile: "pw-secret"},
+		},
+	}
+	for _, tt := range tests {
+		var sources []int
+		for s := fromFile; s <= fromSecretFile; s++ {
+			if _, ok := tt.values[s]; ok {
+				sources = append(sources, s)
+			}
+		}
+		// Try every combination of the sources the key supports.
+		for mask := 0; mask < 1<<len(sources); mask++ {
+			set := map[int]bool{}
+			var names []string
+			want := tt.values[fromDefault]
+			for i, s := range sources {
+				if mask&(1<<i) != 0 {
+					set[s] = true
+					names = append(names, sourceNames[s])
+					want = tt.values[s]
+				}
+			}
+			if len(names) == 0 {
+				names = []string{sourceNames[fromDefault]}
+			}
+			t.Run(tt.key+"/"+strings.Join(names, "+"), func(t *testing.T) {
+				got := tt.get(loadFromSources(t, tt.key, tt.values, set))
+				if got != want {
+					t.Errorf("%s = %q, want %q", tt.key, got, want)
+				}
+			})
+		}
+	}
+}
+
+// loadFromSources parses a Config in which key is set by each source in set
+// to its entry in values.
+func loadFromSources(t *testing.T, key string, values map[int]string, set map[int]bool) *Config {
+	t.Helper()
+	envName := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+
+	doc := ""
+	if set[fromFile] {
+		section, field, _ := strings.Cut(key, ".")
+		doc = section + ":\n  " + field + ": " + values[fromFile] + "\n"
+	}
+	if set[fromEnv] {
+		t.Setenv(envName, values[fromEnv])
+	}
+	if set[fromSecretFile] {
+		t.Setenv(envName+secretFileSuffix, writeFile(t, t.TempDir(), "secret", values[fromSecretFile]+"\n"))
+	}
+	v, err := LoadConfigFromReader(strings.NewReader(doc), "yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := pflag.NewFlagSet("automart", pflag.ContinueOnError)
+	RegisterFlags(fs)
+	if err := BindFlags(v, fs); err != nil {
+		t.Fatal(err)
+	}
+	if set[fromFlag] {
+		if fs.Lookup(key) == nil {
+			// Not a command line flag; bind one the way BindFlags does to
+			// check a secret file also wins over flags.
+			fs.String(key, "", "")
+			if err := v.BindPFlag(key, fs.Lookup(key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := fs.Parse([]string{"--" + key + "=" + values[fromFlag]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := ParsConfig(v)
+	if err != nil {
+		t.Fatalf("ParsConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestEmptyEnvCountsAsUnset(t *testing.T) {
+	t.Setenv("AUTOMART_POSTGRES_MAXOPENCONNS", "")
+	t.Setenv("AUTOMART_POSTGRES_HOST", "")
+	cfg := parseYAML(t, "postgres:\n  host: db.file\n")
+	if cfg.Postgres.MaxOpenConns != 25 || cfg.Postgres.Host != "db.file" {
+		t.Errorf("MaxOpenConns %d, Host %q; want the default and the file value", cfg.Postgres.MaxOpenConns, cfg.Postgres.Host)
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import "strings"
+
+// IsProduction: Reports whether the Config was loaded for APP_ENV=production.
+
+func (c *Config) IsProduction() bool {
+	return c.isEnv("production")
+}
+
+// IsDevelopment: Reports whether the Config was loaded for development,
+// which is also the environment used when APP_ENV is unset.
+
+func (c *Config) IsDevelopment() bool {
+	return c.isEnv("development")
+}
+
+// IsDocker: Reports whether the Config was loaded for APP_ENV=docker.
+
+func (c *Config) IsDocker() bool {
+	return c.isEnv("docker")
+}
+
+// defaultLogLevel: Returns the Logger.Level used when none is configured:
+// "debug" for development and "info" everywhere else.
+
+func (c *Config) defaultLogLevel() string {
+	if c.IsDevelopment() {
+		return "debug"
+	}
+	return "info"
+}
+
+func (c *Config) isEnv(env string) bool {
+	return strings.EqualFold(strings.TrimSpace(c.Env), env)
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentHelpers(t *testing.T) {
+	tests := []struct {
+		env                     string
+		production, development bool
+		docker                  bool
+	}{
+		{env: "production", production: true},
+		{env: "Production", production: true},
+		{env: "PRODUCTION", production: true},
+		{env: " production ", production: true},
+		{env: "development", development: true},
+		{env: "DEVELOPMENT", development: true},
+		{env: "docker", docker: true},
+		{env: "Docker", docker: true},
+		{env: "staging"},
+		{env: ""},
+	}
+	for _, tt := range tests {
+		c := &Config{Env: tt.env}
+		if c.IsProduction() != tt.production || c.IsDevelopment() != tt.development || c.IsDocker() != tt.docker {
+			t.Errorf("Env %q: IsProduction %v, IsDevelopment %v, IsDocker %v; want %v, %v, %v",
+				tt.env, c.IsProduction(), c.IsDevelopment(), c.IsDocker(), tt.production, tt.development, tt.docker)
+		}
+	}
+}
+
+func TestGetConfigSetsEnv(t *testing.T) {
+	dir := useConfigDir(t, "Staging")
+	writeFile(t, dir, "config-staging.yml", validYAML)
+
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if cfg.Env != "staging" {
+		t.Errorf("Env = %q, want the sanitized APP_ENV staging", cfg.Env)
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		appEnv      string
+		strictEnv   string
+		want        string
+		wantWarning bool
+		wantErr     bool
+	}{
+		{name: "set", appEnv: "staging", want: "staging"},
+		{name: "surrounding whitespace", appEnv: "  production\n", want: "production"},
+		{name: "empty", appEnv: "", want: "development", wantWarning: true},
+		{name: "whitespace only", appEnv: " \t ", want: "development", wantWarning: true},
+		{name: "set in strict mode", appEnv: "staging", strictEnv: "true", want: "staging"},
+		{name: "empty in strict mode", appEnv: "", strictEnv: "true", wantErr: true},
+		{name: "whitespace only in strict mode", appEnv: "  ", strictEnv: " TRUE ", wantErr: true},
+		{name: "strict mode off", appEnv: "", strictEnv: "false", want: "development", wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("STRICT_ENV", tt.strictEnv)
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			env, err := resolveEnv()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "STRICT_ENV") {
+					t.Fatalf("resolveEnv() = %q, %v, want a STRICT_ENV error", env, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveEnv: %v", err)
+			}
+			if env != tt.want {
+				t.Errorf("resolveEnv() = %q, want %q", env, tt.want)
+			}
+			warned := strings.Contains(logs.String(), `APP_ENV is not set, defaulting to "development"`)
+			if warned != tt.wantWarning {
+				t.Errorf("warning logged = %v, want %v; log: %q", warned, tt.wantWarning, logs.String())
+			}
+		})
+	}
+}
+
+func TestGetConfigDefaultLogLevel(t *testing.T) {
+	tests := []struct {
+		env   string
+		level string // Logger.Level in the config file
+		want  string
+	}{
+		{env: "development", want: "debug"},
+		{env: "production", want: "info"},
+		{env: "staging", want: "info"},
+		{env: "development", level: "warn", want: "warn"},
+		{env: "production", level: "debug", want: "debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"/"+tt.level, func(t *testing.T) {
+			dir := useConfigDir(t, tt.env)
+			doc := validYAML
+			if tt.level != "" {
+				doc += "logger:\n  level: " + tt.level + "\n"
+			}
+			writeFile(t, dir, "config-"+tt.env+".yml", doc)
+
+			cfg, err := GetConfig()
+			if err != nil {
+				t.Fatalf("GetConfig: %v", err)
+			}
+			if cfg.Logger.Level != tt.want {
+				t.Errorf("Logger.Level = %q, want %q", cfg.Logger.Level, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// extendsKey is the top-level key naming the profile a config file builds
+// on, e.g. "extends: production" in config-staging.yml.
+const extendsKey = "extends"
+
+// extendsChain: Follows the extends keys starting at filename and returns the
+// config file names to merge, the root profile first and filename last. A
+// profile extended but missing from configPath, or a chain that loops back
+// on itself, is an error.
+
+func extendsChain(filename, fileType, configPath string) ([]string, error) {
+	chain := []string{filename}
+	for name := filename; ; {
+		parent, err := readExtends(filepath.Join(configPath, name+"."+fileType), fileType)
+		if err != nil {
+			return nil, err
+		}
+		if parent == "" {
+			break
+		}
+		parentName := getConfigFileName(parent)
+		if slices.Contains(chain, parentName) {
+			return nil, fmt.Errorf("circular extends: %s -> %s", strings.Join(chain, " -> "), parentName)
+		}
+		if !fileExists(filepath.Join(configPath, parentName+"."+fileType)) {
+			return nil, fmt.Errorf("%s extends %q, but %s.%s does not exist in %s", name, parent, parentName, fileType, configPath)
+		}
+		chain = append(chain, parentName)
+		name = parentName
+	}
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// readExtends: Returns the extends value of the config file at path, or ""
+// when the file does not exist or has none.
+
+func readExtends(path, fileType string) (string, error) {
+	if !fileExists(path) {
+		return "", nil
+	}
+	v := viper.New()
+	v.SetConfigType(fileType)
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return "", fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	return strings.TrimSpace(v.GetString(extendsKey)), nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtends(t *testing.T) {
+	production := strings.Replace(validYAML, "host: cache.internal", "host: cache.production", 1) +
+		"logger:\n  level: warn\n"
+	tests := []struct {
+		name  string
+		files map[string]string
+		check func(t *testing.T, c *Config)
+	}{
+		{
+			name: "single level",
+			files: map[string]string{
+				"config-production.yml": production,
+				"config-staging.yml":    "extends: production\npostgres:\n  host: db.staging\n",
+			},
+			check: func(t *testing.T, c *Config) {
+				if c.Postgres.Host != "db.staging" || c.Redis.Host != "cache.production" || c.Logger.Level != "warn" {
+					t.Errorf("Postgres.Host %q, Redis.Host %q, Logger.Level %q; want the staging host over production values",
+						c.Postgres.Host, c.Redis.Host, c.Logger.Level)
+				}
+			},
+		},
+		{
+			name: "multi level",
+			files: map[string]string{
+				"config-production.yml": production,
+				"config-qa.yml":         "extends: production\nlogger:\n  level: info\npostgres:\n  host: db.qa\n",
+				"config-staging.yml":    "extends: qa\npostgres:\n  host: db.staging\n",
+			},
+			check: func(t *testing.T, c *Config) {
+				if c.Postgres.Host != "db.staging" || c.Logger.Level != "info" || c.Redis.Host != "cache.production" {
+					t.Errorf("Postgres.Host %q, Logger.Level %q, Redis.Host %q; want staging over qa over production",
+						c.Postgres.Host, c.Logger.Level, c.Redis.Host)
+				}
+			},
+		},
+		{
+			name: "base is merged below the chain",
+			files: map[string]string{
+				"config-base.yml":       "redis:\n  host: cache.base\n  poolSize: 42\n",
+				"config-production.yml": production,
+				"config-staging.yml":    "extends: production\n",
+			},
+			check: func(t *testing.T, c *Config) {
+				if c.Redis.Host != "cache.production" || c.Redis.PoolSize != 42 {
+					t.Errorf("Redis.Host %q, PoolSize %d; want production over base", c.Redis.Host, c.Redis.PoolSize)
+				}
+			},
+		},
+		{
+			name:  "no extends",
+			files: map[string]string{"config-staging.yml": validYAML},
+			check: func(t *testing.T, c *Config) {
+				if c.Redis.Host != "cache.internal" {
+					t.Errorf("Redis.Host = %q", c.Redis.Host)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+			tt.check(t, loadDir(t, dir))
+		})
+	}
+}
+
+func TestExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config-production.yml": "extends: staging\n" + validYAML,
+				"config-staging.yml":    "extends: production\n",
+			},
+			wantErr: "circular extends: config-staging -> config-production -> config-staging",
+		},
+		{
+			name: "longer cycle",
+			files: map[string]string{
+				"config-production.yml": "extends: qa\n" + validYAML,
+				"config-qa.yml":         "extends: staging\n",
+				"config-staging.yml":    "extends: production\n",
+			},
+			wantErr: "circular extends: config-staging -> config-production -> config-qa -> config-staging",
+		},
+		{
+			name:    "extends itself",
+			files:   map[string]string{"config-staging.yml": "extends: staging\n" + validYAML},
+			wantErr: "circular extends: config-staging -> config-staging",
+		},
+		{
+			name:    "missing profile",
+			files:   map[string]string{"config-staging.yml": "extends: production\n"},
+			wantErr: `config-staging extends "production", but config-production.yml does not exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+			_, err := LoadConfig("config-staging", "yml", dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package config
+
+import "strings"
+
+// IsFeatureEnabled: Reports whether the named flag in Features is on; unknown
+// flags are off. Names are case-insensitive because viper lower-cases map
+// keys. To pick up toggles from a hot reload, call it on the Config returned
+// by Get, which Watch swaps on every valid change.
+
+func (c *Config) IsFeatureEnabled(name string) bool {
+	return c.Features[strings.ToLower(name)]
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsFeatureEnabled(t *testing.T) {
+	cfg := parseYAML(t, validYAML+"features:\n  newCheckout: true\n  legacySearch: false\n")
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "newCheckout", want: true},
+		{name: "NEWCHECKOUT", want: true},
+		{name: "legacySearch", want: false},
+		{name: "unknown", want: false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsFeatureEnabled(tt.name); got != tt.want {
+			t.Errorf("IsFeatureEnabled(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if (&Config{}).IsFeatureEnabled("newCheckout") {
+		t.Error("a Config without Features reports a flag as enabled")
+	}
+}
+
+func TestFeatureToggleOnReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config-staging.yml", validYAML+"features:\n  newCheckout: false\n")
+	cfg := loadDir(t, dir)
+	cfg.Env = "staging"
+
+	flipped := make(chan bool, 16)
+	cfg.Watch(func(next *Config) { flipped <- next.IsFeatureEnabled("newCheckout") })
+	writeFile(t, dir, "config-staging.yml", validYAML+"features:\n  newCheckout: true\n")
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case on := <-flipped:
+			if !on {
+				continue
+			}
+			if !cfg.latest().IsFeatureEnabled("newCheckout") {
+				t.Error("the reloaded Config does not have the flag on")
+			}
+			return
+		case <-timeout:
+			t.Fatal("newCheckout was not switched on after rewriting the file")
+		}
+	}
+}
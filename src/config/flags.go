@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// flagKeys are the config keys that can be overridden from the command line,
+// e.g. --server.port 9000.
+var flagKeys = []struct {
+	key   string
+	usage string
+}{
+	{"server.host", "HTTP server bind host"},
+	{"server.port", "HTTP server port"},
+	{"server.runMode", "gin run mode (debug, release, test)"},
+	{"postgres.host", "Postgres host"},
+	{"postgres.port", "Postgres port"},
+	{"postgres.user", "Postgres user"},
+	{"postgres.dbName", "Postgres database name"},
+	{"redis.host", "Redis host"},
+	{"redis.port", "Redis port"},
+	{"logger.level", "log level"},
+}
+
+// RegisterFlags: Defines a flag on fs for each overridable config key that
+// fs does not define yet.
+
+func RegisterFlags(fs *pflag.FlagSet) {
+	for _, f := range flagKeys {
+		if fs.Lookup(f.key) == nil {
+			fs.String(f.key, "", f.usage)
+		}
+	}
+}
+
+// BindFlags: Registers the config flags on fs and binds them to v. Flags the
+// user actually passed take precedence over env vars, the config file and
+// defaults; unset flags do not override anything. Other flags on fs, such as
+// --check-config, are not config keys and are left unbound.
+
+func BindFlags(v *viper.Viper, fs *pflag.FlagSet) error {
+	RegisterFlags(fs)
+	for _, f := range flagKeys {
+		if err := v.BindPFlag(f.key, fs.Lookup(f.key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestBindFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		wantPort string
+		wantHost string
+	}{
+		{name: "unset flags override nothing", wantPort: "5005", wantHost: "db.internal"},
+		{name: "flag overrides the file", args: []string{"--server.port", "9000"}, wantPort: "9000", wantHost: "db.internal"},
+		{name: "flag overrides env", args: []string{"--postgres.host=db.flag"}, env: "db.env", wantPort: "5005", wantHost: "db.flag"},
+		{name: "env applies without the flag", env: "db.env", wantPort: "5005", wantHost: "db.env"},
+		{name: "other flags are ignored", args: []string{"--check-config"}, wantPort: "5005", wantHost: "db.internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("AUTOMART_POSTGRES_HOST", tt.env)
+			}
+			fs := pflag.NewFlagSet("automart", pflag.ContinueOnError)
+			fs.Bool("check-config", false, "validate the config and exit")
+			RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			v, err := LoadConfigFromReader(strings.NewReader(validYAML), "yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := BindFlags(v, fs); err != nil {
+				t.Fatalf("BindFlags: %v", err)
+			}
+			cfg, err := ParsConfig(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != tt.wantPort || cfg.Postgres.Host != tt.wantHost {
+				t.Errorf("got Server.Port %q, Postgres.Host %q; want %q, %q", cfg.Server.Port, cfg.Postgres.Host, tt.wantPort, tt.wantHost)
+			}
+			if v.IsSet("check-config") {
+				t.Error("--check-config was bound as a config key")
+			}
+		})
+	}
+}
+
+func TestRegisterFlagsKeepsExistingFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("automart", pflag.ContinueOnError)
+	fs.Int("server.port", 1, "custom")
+	RegisterFlags(fs)
+	RegisterFlags(fs) // must not panic on redefinition
+	if got := fs.Lookup("server.port").Usage; got != "custom" {
+		t.Errorf("server.port usage = %q, want the existing flag kept", got)
+	}
+}
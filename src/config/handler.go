@@ -0,0 +1,24 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ConfigHandler: Serves the redacted Config as JSON for debugging a running
+// instance. It answers 403 in production, whatever the run mode, and when
+// the server runs in release mode, so the endpoint is only reachable in
+// debug and test deployments. After a reload through Watch or
+// ReloadOnSignal it serves the reloaded Config rather than c.
+
+func ConfigHandler(c *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		current := c.latest()
+		if current.IsProduction() || current.Server.GinMode() == "release" {
+			http.Error(w, "config endpoint is disabled in production and release mode", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(current.Redacted())
+	}
+}
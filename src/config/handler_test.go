@@ -0,0 +1,72 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		runMode string
+		status  int
+	}{
+		{name: "debug", env: "staging", runMode: "debug", status: http.StatusOK},
+		{name: "test mode", env: "development", runMode: "test", status: http.StatusOK},
+		{name: "release", env: "staging", runMode: "release", status: http.StatusForbidden},
+		{name: "production in debug mode", env: "production", runMode: "debug", status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Env = tt.env
+			cfg.Server.RunMode = tt.runMode
+
+			w := httptest.NewRecorder()
+			ConfigHandler(cfg)(w, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			body := w.Body.String()
+			for _, want := range []string{`"Host":"db.internal"`, `"Port":"5432"`, `"Password":"****"`} {
+				if !strings.Contains(body, want) {
+					t.Errorf("body lacks %s: %s", want, body)
+				}
+			}
+			for _, secret := range []string{"pg-secret", "redis-secret", cfg.Auth.SecretKey} {
+				if strings.Contains(body, secret) {
+					t.Errorf("body leaks %q", secret)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigHandlerServesReloadedConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config-staging.yml", validYAML)
+	cfg := loadDir(t, dir)
+	cfg.Env = "staging"
+
+	reloaded := make(chan struct{}, 16)
+	cfg.Watch(func(*Config) { reloaded <- struct{}{} })
+	writeFile(t, dir, "config-staging.yml", strings.Replace(validYAML, "host: db.internal", "host: db.reloaded", 1))
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+
+	w := httptest.NewRecorder()
+	ConfigHandler(cfg)(w, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	if !strings.Contains(w.Body.String(), `"Host":"db.reloaded"`) {
+		t.Errorf("handler serves the config it was built with: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import "sync"
+
+var (
+	instance     *Config
+	instanceOnce sync.Once
+	// instanceMu guards instance, which Watch swaps on reload.
+	instanceMu sync.RWMutex
+)
+
+// Get: Returns the process-wide Config, loading it on the first call only.
+// Concurrent first callers block until the load finishes and all receive the
+// same pointer. Like MustGetConfig, a load failure terminates the process.
+// After a hot reload (see Watch) Get returns the reloaded Config.
+
+func Get() *Config {
+	instanceOnce.Do(func() {
+		cfg := MustGetConfig()
+		instanceMu.Lock()
+		instance = cfg
+		instanceMu.Unlock()
+	})
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	return instance
+}
+
+// Reset: Drops the cached Config so the next Get loads it again. Meant for
+// tests; it must not run concurrently with Get.
+
+func Reset() {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+	instanceOnce = sync.Once{}
+	instance = nil
+}
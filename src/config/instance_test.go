@@ -0,0 +1,43 @@
+package config
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGetLoadsOnce(t *testing.T) {
+	dir := useConfigDir(t, "staging")
+	writeFile(t, dir, "config-staging.yml", validYAML)
+	Reset()
+	t.Cleanup(Reset)
+
+	const callers = 32
+	got := make([]*Config, callers)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = Get()
+		}()
+	}
+	wg.Wait()
+
+	for i, cfg := range got {
+		if cfg == nil || cfg != got[0] {
+			t.Fatalf("caller %d got %p, want the shared instance %p", i, cfg, got[0])
+		}
+	}
+
+	// Later loads come from the cache, not the file.
+	writeFile(t, dir, "config-staging.yml", "server: [broken\n")
+	if Get() != got[0] {
+		t.Error("Get reloaded the config after the first call")
+	}
+
+	Reset()
+	writeFile(t, dir, "config-staging.yml", validYAML)
+	if Get() == got[0] {
+		t.Error("Get returned the old instance after Reset")
+	}
+}
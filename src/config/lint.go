@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// CheckUnknownKeys: Returns, sorted, the keys set in v that no Config field
+// consumes, such as a misspelled "postgres.maxopenconn", which viper would
+// otherwise drop without a word. Keys below map fields (Features,
+// Logger.Levels) are free-form and never reported.
+
+func CheckUnknownKeys(v *viper.Viper) []string {
+	known := map[string]bool{extendsKey: true}
+	for _, key := range configKeys() {
+		known[key] = true
+	}
+	maps := mapKeys(reflect.TypeOf(Config{}), "")
+
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if known[key] || slices.ContainsFunc(maps, func(m string) bool { return strings.HasPrefix(key, m+".") }) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// mapKeys: Returns the viper keys of the map-typed fields below t.
+
+func mapKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		key := prefix + strings.ToLower(f.Name)
+		switch f.Type.Kind() {
+		case reflect.Struct:
+			keys = append(keys, mapKeys(f.Type, key+".")...)
+		case reflect.Map:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
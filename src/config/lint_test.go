@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCheckUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+		want  []string
+	}{
+		{name: "only known keys"},
+		{name: "misspelled key", extra: "postgres:\n  maxopenconn: 20\n", want: []string{"postgres.maxopenconn"}},
+		{name: "unknown section", extra: "mailer:\n  host: smtp\n", want: []string{"mailer.host"}},
+		{
+			name:  "several, sorted",
+			extra: "server:\n  prot: 80\nlogger:\n  levle: debug\n",
+			want:  []string{"logger.levle", "server.prot"},
+		},
+		{name: "keys are case-insensitive", extra: "postgres:\n  MaxOpenConns: 20\n"},
+		{name: "extends is consumed by the loader", extra: "extends: production\n"},
+		{name: "keys below a map are free-form", extra: "features:\n  newCheckout: true\nlogger:\n  levels:\n    gorm: warn\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Merge the extra document so a section already in validYAML is extended.
+			v, err := LoadConfigFromReader(strings.NewReader(validYAML), "yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.extra != "" {
+				if err := v.MergeConfig(strings.NewReader(tt.extra)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := CheckUnknownKeys(v); !slices.Equal(got, tt.want) {
+				t.Errorf("CheckUnknownKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnknownKeysIgnoresOtherFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("automart", pflag.ContinueOnError)
+	fs.Bool("check-config", false, "validate the config and exit")
+	RegisterFlags(fs)
+	if err := fs.Parse([]string{"--check-config", "--server.port", "9000"}); err != nil {
+		t.Fatal(err)
+	}
+	v, err := LoadConfigFromReader(strings.NewReader(validYAML), "yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := BindFlags(v, fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := CheckUnknownKeys(v); len(got) != 0 {
+		t.Errorf("CheckUnknownKeys() = %q, want no flags reported", got)
+	}
+}
+
+func TestBuildConfigReportsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		strict  string
+		wantErr bool
+	}{
+		{strict: ""},
+		{strict: "false"},
+		{strict: "true", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run("STRICT_CONFIG="+tt.strict, func(t *testing.T) {
+			t.Setenv("STRICT_CONFIG", tt.strict)
+			doc := strings.Replace(validYAML, "postgres:\n", "postgres:\n  maxopenconn: 20\n", 1)
+			v, err := LoadConfigFromReader(strings.NewReader(doc), "yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			_, err = buildConfig(v, "staging", nil)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unknown config keys: postgres.maxopenconn") {
+					t.Errorf("buildConfig error = %v, want it to name postgres.maxopenconn", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildConfig: %v", err)
+			}
+			if !strings.Contains(logs.String(), "ignoring unknown keys postgres.maxopenconn") {
+				t.Errorf("log %q does not warn about postgres.maxopenconn", logs.String())
+			}
+		})
+	}
+}
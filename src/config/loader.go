@@ -0,0 +1,34 @@
+package config
+
+import "errors"
+
+// Loader: Supplies a Config. Code that needs configuration can accept a
+// Loader instead of calling GetConfig, so tests can pass a StaticLoader
+// rather than preparing files on disk.
+type Loader interface {
+	Load() (*Config, error)
+}
+
+// FileLoader: Loads the Config the way GetConfig does, from the config file
+// for APP_ENV or the source selected by CONFIG_SOURCE.
+type FileLoader struct{}
+
+// Load: Calls GetConfig.
+
+func (FileLoader) Load() (*Config, error) {
+	return GetConfig()
+}
+
+// StaticLoader: Returns a fixed Config, typically built in a test.
+type StaticLoader struct {
+	Config *Config
+}
+
+// Load: Returns s.Config, or an error when it is nil.
+
+func (s StaticLoader) Load() (*Config, error) {
+	if s.Config == nil {
+		return nil, errors.New("StaticLoader has no Config")
+	}
+	return s.Config, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// listenAddress stands in for a service that takes its config from a Loader.
+func listenAddress(l Loader) (string, error) {
+	cfg, err := l.Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Server.Address(), nil
+}
+
+func TestLoaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		loader  func(t *testing.T) Loader
+		want    string
+		wantErr string
+	}{
+		{
+			name: "StaticLoader without disk",
+			loader: func(t *testing.T) Loader {
+				// An empty config directory proves nothing is read from disk.
+				useConfigDir(t, "staging")
+				return StaticLoader{Config: &Config{Server: ServerConfig{Host: "127.0.0.1", Port: "9090"}}}
+			},
+			want: "127.0.0.1:9090",
+		},
+		{
+			name:    "StaticLoader without a Config",
+			loader:  func(t *testing.T) Loader { return StaticLoader{} },
+			wantErr: "StaticLoader has no Config",
+		},
+		{
+			name: "FileLoader reads the config file",
+			loader: func(t *testing.T) Loader {
+				dir := useConfigDir(t, "staging")
+				writeFile(t, dir, "config-staging.yml", validYAML)
+				return FileLoader{}
+			},
+			want: ":5005",
+		},
+		{
+			name: "FileLoader without a config file",
+			loader: func(t *testing.T) Loader {
+				useConfigDir(t, "staging")
+				return FileLoader{}
+			},
+			wantErr: "config file not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listenAddress(tt.loader(t))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("address = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import "fmt"
+
+// Normalize: Turns a requested 1-based page and page size into an offset and
+// limit for a query. A non-positive page becomes 1, a non-positive size
+// becomes DefaultPageSize and sizes above MaxPageSize are clamped to it.
+
+func (p PaginationConfig) Normalize(page, size int) (offset, limit int) {
+	if page <= 0 {
+		page = 1
+	}
+	limit = size
+	if limit <= 0 {
+		limit = p.DefaultPageSize
+	}
+	if p.MaxPageSize > 0 && limit > p.MaxPageSize {
+		limit = p.MaxPageSize
+	}
+	return (page - 1) * limit, limit
+}
+
+// validate: Checks that both sizes are positive and DefaultPageSize does not
+// exceed MaxPageSize.
+
+func (p PaginationConfig) validate() error {
+	if p.DefaultPageSize <= 0 || p.MaxPageSize <= 0 {
+		return fmt.Errorf("invalid Pagination: DefaultPageSize %d and MaxPageSize %d must be positive", p.DefaultPageSize, p.MaxPageSize)
+	}
+	if p.DefaultPageSize > p.MaxPageSize {
+		return fmt.Errorf("invalid Pagination.DefaultPageSize %d: must not exceed MaxPageSize %d", p.DefaultPageSize, p.MaxPageSize)
+	}
+	return nil
+}
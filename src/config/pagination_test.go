@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPaginationNormalize(t *testing.T) {
+	p := PaginationConfig{DefaultPageSize: 20, MaxPageSize: 100}
+	tests := []struct {
+		name       string
+		page, size int
+		offset     int
+		limit      int
+	}{
+		{name: "first page", page: 1, size: 10, offset: 0, limit: 10},
+		{name: "third page", page: 3, size: 10, offset: 20, limit: 10},
+		{name: "zero page and size", page: 0, size: 0, offset: 0, limit: 20},
+		{name: "negative page and size", page: -2, size: -5, offset: 0, limit: 20},
+		{name: "oversized request", page: 2, size: 500, offset: 100, limit: 100},
+		{name: "exactly the maximum", page: 1, size: 100, offset: 0, limit: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, limit := p.Normalize(tt.page, tt.size)
+			if offset != tt.offset || limit != tt.limit {
+				t.Errorf("Normalize(%d, %d) = %d, %d; want %d, %d", tt.page, tt.size, offset, limit, tt.offset, tt.limit)
+			}
+		})
+	}
+}
+
+func TestPaginationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       PaginationConfig
+		wantErr string
+	}{
+		{name: "defaults", p: PaginationConfig{DefaultPageSize: 20, MaxPageSize: 100}},
+		{name: "equal sizes", p: PaginationConfig{DefaultPageSize: 50, MaxPageSize: 50}},
+		{name: "default above max", p: PaginationConfig{DefaultPageSize: 200, MaxPageSize: 100}, wantErr: "invalid Pagination.DefaultPageSize 200: must not exceed MaxPageSize 100"},
+		{name: "zero max", p: PaginationConfig{DefaultPageSize: 20}, wantErr: "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortInt: Returns the port the server listens on as a number, InternalPort
+// when set and Port otherwise, like Address.
+
+func (s ServerConfig) PortInt() (int, error) {
+	if s.InternalPort != "" {
+		return ParsePort("Server.InternalPort", s.InternalPort)
+	}
+	return ParsePort("Server.Port", s.Port)
+}
+
+// validatePorts: Reports every pair of non-empty port fields sharing a value,
+// compared as numbers so "08080" and "8080" collide. ExternalPort is left out
+// with IgnoreExternalPortCollision, for setups where it only documents the
+// NAT-mapped port. Unparseable ports are reported by the port tag instead.
+
+func (s ServerConfig) validatePorts() []error {
+	fields := []struct{ name, value string }{
+		{"Server.InternalPort", s.InternalPort},
+		{"Server.Port", s.Port},
+		{"Server.MetricsPort", s.MetricsPort},
+	}
+	if !s.IgnoreExternalPortCollision {
+		fields = append(fields, struct{ name, value string }{"Server.ExternalPort", s.ExternalPort})
+	}
+
+	var errs []error
+	seen := map[int]string{}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		port, err := ParsePort(f.name, f.value)
+		if err != nil {
+			continue
+		}
+		if other, ok := seen[port]; ok {
+			errs = append(errs, fmt.Errorf("%s and %s are both set to port %d", other, f.name, port))
+			continue
+		}
+		seen[port] = f.name
+	}
+	return errs
+}
+
+// PortInt: Returns Port as a number.
+
+func (p PostgresConfig) PortInt() (int, error) {
+	return ParsePort("Postgres.Port", p.Port)
+}
+
+// PortInt: Returns Port as a number.
+
+func (r PostgresReplicaConfig) PortInt() (int, error) {
+	return ParsePort("PostgresReplica.Port", r.Port)
+}
+
+// ParsePort: Parses a port kept as a string, whether it was quoted in YAML or
+// not, and checks that it is between 1 and 65535.
+
+func ParsePort(name, value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s %q: must be a port number between 1 and 65535", name, value)
+	}
+	return port, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPortInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   string // YAML value of server.port
+		postgres string // YAML value of postgres.port
+		want     int
+		wantErr  string
+	}{
+		{name: "unquoted", server: "5005", postgres: "5432", want: 5005},
+		{name: "quoted", server: `"5005"`, postgres: `'5432'`, want: 5005},
+		{name: "leading zero", server: `"05005"`, postgres: "5432", want: 5005},
+		{name: "server port zero", server: "0", postgres: "5432", wantErr: `invalid Server.Port "0"`},
+		{name: "server port too large", server: "65536", postgres: "5432", wantErr: `invalid Server.Port "65536": must be a port number between 1 and 65535`},
+		{name: "postgres port not a number", server: "5005", postgres: "pg", wantErr: `invalid Postgres.Port "pg"`},
+		{name: "postgres port negative", server: "5005", postgres: "-1", wantErr: `invalid Postgres.Port "-1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := strings.Replace(validYAML, "port: 5005", "port: "+tt.server, 1)
+			doc = strings.Replace(doc, "port: 5432", "port: "+tt.postgres, 1)
+			cfg := parseYAML(t, doc)
+
+			server, serverErr := cfg.Server.PortInt()
+			pg, pgErr := cfg.Postgres.PortInt()
+			if replica, err := PostgresReplicaConfig(cfg.Postgres).PortInt(); replica != pg || (err == nil) != (pgErr == nil) {
+				t.Errorf("PostgresReplica PortInt() = %d, %v; want it to match Postgres: %d, %v", replica, err, pg, pgErr)
+			}
+			if tt.wantErr != "" {
+				err := serverErr
+				if err == nil {
+					err = pgErr
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PortInt error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if serverErr != nil || pgErr != nil {
+				t.Fatalf("PortInt: %v, %v", serverErr, pgErr)
+			}
+			if server != tt.want || pg != 5432 {
+				t.Errorf("PortInt() = %d and %d, want %d and 5432", server, pg, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerPortIntPrefersInternalPort(t *testing.T) {
+	tests := []struct {
+		cfg     ServerConfig
+		want    int
+		wantErr string
+	}{
+		{cfg: ServerConfig{Port: "5005"}, want: 5005},
+		{cfg: ServerConfig{Port: "5005", InternalPort: "8080"}, want: 8080},
+		{cfg: ServerConfig{Port: "5005", InternalPort: "80800"}, wantErr: "invalid Server.InternalPort"},
+	}
+	for _, tt := range tests {
+		got, err := tt.cfg.PortInt()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("PortInt() for %+v error = %v, want %q", tt.cfg, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("PortInt() for %+v = %d, %v, want %d", tt.cfg, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidatePortCollisions(t *testing.T) {
+	tests := []struct {
+		name   string
+		server ServerConfig
+		want   []string
+	}{
+		{name: "distinct ports", server: ServerConfig{Port: "5005", InternalPort: "8080", ExternalPort: "80", MetricsPort: "9090"}},
+		{name: "empty ports never collide", server: ServerConfig{Port: "5005"}},
+		{name: "port and internal port", server: ServerConfig{Port: "8080", InternalPort: "8080"}, want: []string{"Server.InternalPort and Server.Port are both set to port 8080"}},
+		{name: "compared as numbers", server: ServerConfig{Port: "5005", MetricsPort: "05005"}, want: []string{"Server.Port and Server.MetricsPort are both set to port 5005"}},
+		{name: "external port collides", server: ServerConfig{Port: "5005", ExternalPort: "5005"}, want: []string{"Server.Port and Server.ExternalPort are both set to port 5005"}},
+		{name: "external port excluded", server: ServerConfig{Port: "5005", ExternalPort: "5005", IgnoreExternalPortCollision: true}},
+		{
+			name:   "every collision is reported",
+			server: ServerConfig{Port: "5005", InternalPort: "5005", MetricsPort: "5005"},
+			want:   []string{"Server.InternalPort and Server.Port", "Server.InternalPort and Server.MetricsPort"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.server.validatePorts()
+			if len(errs) != len(tt.want) {
+				t.Fatalf("validatePorts() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReportsPortCollision(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Server.InternalPort = cfg.Server.Port
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "Server.InternalPort and Server.Port are both set to port 5005") {
+		t.Errorf("Validate() = %v, want the port collision", err)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DSN: Returns the keyword/value connection string understood by libpq and pgx,
+// e.g. "host=localhost port=5432 user=postgres password=admin dbname=car_sale_db sslmode=disable".
+
+func (p PostgresConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(p.Host), dsnValue(p.Port), dsnValue(p.User), dsnValue(p.Password),
+		dsnValue(p.DbName), dsnValue(p.SSLMode))
+}
+
+// URL: Returns the postgres:// URL form of the connection string. User and
+// password are escaped, so characters such as '@', ':' and '/' are safe.
+
+func (p PostgresConfig) URL() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(p.User, p.Password),
+		Host:   net.JoinHostPort(p.Host, p.Port),
+		Path:   "/" + p.DbName,
+	}
+	if p.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {p.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// dsnValue: Quotes a keyword/value DSN value when it is empty or contains
+// spaces, quotes or backslashes, as described in the libpq documentation.
+
+func dsnValue(s string) string {
+	if s != "" && !strings.ContainsAny(s, ` '\`) {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(s) + "'"
+}
+
+// Configured: Reports whether a replica was configured at all.
+
+func (r PostgresReplicaConfig) Configured() bool {
+	return r.Host != ""
+}
+
+// checkProxyIdleTimeout: Reports a ConnMaxLifetime that is not below
+// ProxyIdleTimeout, so pooled connections would outlive the idle timeout of
+// PgBouncer or a cloud proxy in front of Postgres and be cut under the pool.
+// The error suggests a lifetime 10% below the proxy timeout. Nothing is
+// checked while ProxyIdleTimeout is unset.
+
+func (p PostgresConfig) checkProxyIdleTimeout(section string) error {
+	if p.ProxyIdleTimeout <= 0 || (p.ConnMaxLifetime > 0 && p.ConnMaxLifetime < p.ProxyIdleTimeout) {
+		return nil
+	}
+	if p.ConnMaxLifetime == 0 {
+		return fmt.Errorf("%s.ConnMaxLifetime is unlimited but %s.ProxyIdleTimeout is %s; set ConnMaxLifetime to at most %s",
+			section, section, p.ProxyIdleTimeout, safeLifetime(p.ProxyIdleTimeout))
+	}
+	return fmt.Errorf("%s.ConnMaxLifetime %s is not below %s.ProxyIdleTimeout %s; set it to at most %s",
+		section, p.ConnMaxLifetime, section, p.ProxyIdleTimeout, safeLifetime(p.ProxyIdleTimeout))
+}
+
+func safeLifetime(proxyIdle time.Duration) time.Duration {
+	safe := proxyIdle * 9 / 10
+	if rounded := safe.Truncate(time.Second); rounded > 0 {
+		return rounded
+	}
+	return safe
+}
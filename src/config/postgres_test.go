@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{
+			name:     "plain",
+			password: "secret",
+			want:     "host=db port=5432 user=app password=secret dbname=automart sslmode=disable",
+		},
+		{
+			name:     "space and quote",
+			password: `it's a secret`,
+			want:     `host=db port=5432 user=app password='it\'s a secret' dbname=automart sslmode=disable`,
+		},
+		{
+			name:     "empty",
+			password: "",
+			want:     "host=db port=5432 user=app password='' dbname=automart sslmode=disable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PostgresConfig{Host: "db", Port: "5432", User: "app", Password: tt.password, DbName: "automart", SSLMode: "disable"}
+			if got := p.DSN(); got != tt.want {
+				t.Errorf("DSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresURLEscapesPassword(t *testing.T) {
+	for _, password := range []string{"p@ss", "p:ss", "p/ss", "a@b:c/d"} {
+		t.Run(password, func(t *testing.T) {
+			p := PostgresConfig{Host: "db", Port: "5432", User: "app", Password: password, DbName: "automart", SSLMode: "require"}
+
+			u, err := url.Parse(p.URL())
+			if err != nil {
+				t.Fatalf("URL() = %q does not parse: %v", p.URL(), err)
+			}
+			got, _ := u.User.Password()
+			if got != password || u.User.Username() != "app" {
+				t.Errorf("URL() = %q carries user %q, password %q; want app, %q", p.URL(), u.User.Username(), got, password)
+			}
+			if u.Host != "db:5432" || u.Path != "/automart" || u.Query().Get("sslmode") != "require" {
+				t.Errorf("URL() = %q, want host db:5432, path /automart, sslmode=require", p.URL())
+			}
+		})
+	}
+}
+
+func TestValidateProxyIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		lifetime    time.Duration
+		proxyIdle   time.Duration
+		replica     bool
+		wantMessage string // empty when nothing is reported
+	}{
+		{name: "no proxy", lifetime: time.Hour},
+		{name: "below the proxy timeout", lifetime: 5 * time.Minute, proxyIdle: 10 * time.Minute},
+		{name: "equal to the proxy timeout", lifetime: 10 * time.Minute, proxyIdle: 10 * time.Minute,
+			wantMessage: "Postgres.ConnMaxLifetime 10m0s is not below Postgres.ProxyIdleTimeout 10m0s; set it to at most 9m0s"},
+		{name: "above the proxy timeout", lifetime: time.Hour, proxyIdle: 10 * time.Minute,
+			wantMessage: "Postgres.ConnMaxLifetime 1h0m0s is not below Postgres.ProxyIdleTimeout 10m0s; set it to at most 9m0s"},
+		{name: "unlimited lifetime", proxyIdle: 10 * time.Minute,
+			wantMessage: "Postgres.ConnMaxLifetime is unlimited but Postgres.ProxyIdleTimeout is 10m0s; set ConnMaxLifetime to at most 9m0s"},
+		{name: "sub-second suggestion", lifetime: time.Second, proxyIdle: 500 * time.Millisecond,
+			wantMessage: "set it to at most 450ms"},
+		{name: "replica", lifetime: time.Hour, proxyIdle: 10 * time.Minute, replica: true,
+			wantMessage: "PostgresReplica.ConnMaxLifetime 1h0m0s is not below PostgresReplica.ProxyIdleTimeout"},
+	}
+	for _, tt := range tests {
+		for _, env := range []string{"", "false", "true"} {
+			strict := env == "true"
+			t.Run(tt.name+"/STRICT_CONFIG="+env, func(t *testing.T) {
+				t.Setenv("STRICT_CONFIG", env)
+				cfg := validConfig(t)
+				section := &cfg.Postgres
+				if tt.replica {
+					cfg.PostgresReplica = PostgresReplicaConfig(cfg.Postgres)
+					cfg.PostgresReplica.Host = "replica.internal"
+					section = (*PostgresConfig)(&cfg.PostgresReplica)
+				}
+				section.ConnMaxLifetime = tt.lifetime
+				section.ProxyIdleTimeout = tt.proxyIdle
+
+				var logs bytes.Buffer
+				log.SetOutput(&logs)
+				defer log.SetOutput(os.Stderr)
+				err := cfg.Validate()
+
+				switch {
+				case tt.wantMessage == "":
+					if err != nil || strings.Contains(logs.String(), "ProxyIdleTimeout") {
+						t.Errorf("Validate() = %v, log %q; want nothing reported", err, logs.String())
+					}
+				case strict:
+					if err == nil || !strings.Contains(err.Error(), tt.wantMessage) {
+						t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantMessage)
+					}
+				default:
+					if err != nil {
+						t.Errorf("Validate() = %v, want only a warning", err)
+					}
+					if !strings.Contains(logs.String(), tt.wantMessage) || !strings.Contains(logs.String(), "STRICT_CONFIG=true") {
+						t.Errorf("log %q, want a warning containing %q that mentions STRICT_CONFIG", logs.String(), tt.wantMessage)
+					}
+				}
+			})
+		}
+	}
+}
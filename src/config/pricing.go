@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format: Renders an amount given in minor units (e.g. cents) with thousands
+// separators, MinorUnits decimal places and the currency symbol: 123456789
+// becomes "$1,234,567.89" for USD, or "1,234,567.89 $" with SymbolAfter. The
+// currency code is used when Symbol is empty.
+
+func (p PricingConfig) Format(amountMinor int64) string {
+	sign := ""
+	digits := strconv.FormatUint(uint64(amountMinor), 10)
+	if amountMinor < 0 {
+		sign = "-"
+		// Negate in uint64 so math.MinInt64 does not overflow.
+		digits = strconv.FormatUint(-uint64(amountMinor), 10)
+	}
+
+	var fraction string
+	if p.MinorUnits > 0 {
+		if len(digits) <= p.MinorUnits {
+			digits = strings.Repeat("0", p.MinorUnits-len(digits)+1) + digits
+		}
+		fraction = "." + digits[len(digits)-p.MinorUnits:]
+		digits = digits[:len(digits)-p.MinorUnits]
+	}
+	amount := groupThousands(digits) + fraction
+
+	symbol := p.Symbol
+	if symbol == "" {
+		symbol = p.Currency
+	}
+	if p.SymbolAfter {
+		return sign + amount + " " + symbol
+	}
+	return sign + symbol + amount
+}
+
+func groupThousands(digits string) string {
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// validate: Checks that Currency looks like an ISO 4217 code and MinorUnits
+// is in the range ISO 4217 uses.
+
+func (p PricingConfig) validate() []error {
+	var errs []error
+	if len(p.Currency) != 3 || !isUpperLetters(p.Currency) {
+		errs = append(errs, fmt.Errorf("invalid Pricing.Currency %q: must be a 3-letter ISO 4217 code such as USD", p.Currency))
+	}
+	if p.MinorUnits < 0 || p.MinorUnits > 4 {
+		errs = append(errs, fmt.Errorf("invalid Pricing.MinorUnits %d: must be between 0 and 4", p.MinorUnits))
+	}
+	return errs
+}
+
+func isUpperLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPricingFormat(t *testing.T) {
+	usd := PricingConfig{Currency: "USD", MinorUnits: 2, Symbol: "$"}
+	irr := PricingConfig{Currency: "IRR", MinorUnits: 0, Symbol: "﷼", SymbolAfter: true}
+	tests := []struct {
+		name   string
+		p      PricingConfig
+		amount int64
+		want   string
+	}{
+		{name: "large amount", p: usd, amount: 123456789, want: "$1,234,567.89"},
+		{name: "below one unit", p: usd, amount: 5, want: "$0.05"},
+		{name: "zero", p: usd, amount: 0, want: "$0.00"},
+		{name: "negative", p: usd, amount: -250000, want: "-$2,500.00"},
+		{name: "no minor units, symbol after", p: irr, amount: 2_500_000_000, want: "2,500,000,000 ﷼"},
+		{name: "code without a symbol", p: PricingConfig{Currency: "EUR", MinorUnits: 2}, amount: 100000, want: "EUR1,000.00"},
+		{name: "three minor units", p: PricingConfig{Currency: "KWD", MinorUnits: 3, Symbol: "KD "}, amount: 1234567, want: "KD 1,234.567"},
+		{name: "smallest int64", p: irr, amount: math.MinInt64, want: "-9,223,372,036,854,775,808 ﷼"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Format(tt.amount); got != tt.want {
+				t.Errorf("Format(%d) = %q, want %q", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePricing(t *testing.T) {
+	tests := []struct {
+		currency   string
+		minorUnits int
+		wantErr    string
+	}{
+		{currency: "USD", minorUnits: 2},
+		{currency: "IRR", minorUnits: 0},
+		{currency: "usd", minorUnits: 2, wantErr: `invalid Pricing.Currency "usd"`},
+		{currency: "US", minorUnits: 2, wantErr: `invalid Pricing.Currency "US": must be a 3-letter ISO 4217 code`},
+		{currency: "US1", minorUnits: 2, wantErr: `invalid Pricing.Currency "US1"`},
+		{currency: "", minorUnits: 2, wantErr: `invalid Pricing.Currency ""`},
+		{currency: "USD", minorUnits: 5, wantErr: "invalid Pricing.MinorUnits 5: must be between 0 and 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Pricing.Currency = tt.currency
+			cfg.Pricing.MinorUnits = tt.minorUnits
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+// Redis deployment modes for RedisConfig.Mode.
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
+)
+
+var redisModes = []string{RedisModeSingle, RedisModeSentinel, RedisModeCluster}
+
+// Defaults for the Redis timeouts, registered by setDefaults and substituted
+// by Validate for zero values unless Redis.AllowZeroTimeouts is set.
+const (
+	defaultRedisDialTimeout        = 5 * time.Second
+	defaultRedisReadTimeout        = 3 * time.Second
+	defaultRedisWriteTimeout       = 3 * time.Second
+	defaultRedisPoolTimeout        = 4 * time.Second
+	defaultRedisIdleCheckFrequency = time.Minute
+)
+
+// Addr: Returns the Redis address in host:port form, used in single mode.
+
+func (r RedisConfig) Addr() string {
+	return net.JoinHostPort(r.Host, r.Port)
+}
+
+// defaultRedisDatabases is the number of databases a Redis server has unless
+// its "databases" setting says otherwise.
+const defaultRedisDatabases = 16
+
+// DBIndex: Returns Db after checking it addresses one of the server's
+// Databases (16 when unset), so a bad index is reported with the allowed
+// range instead of as a failed SELECT on the first command.
+
+func (r RedisConfig) DBIndex() (int, error) {
+	databases := r.Databases
+	if databases <= 0 {
+		databases = defaultRedisDatabases
+	}
+	if r.Db < 0 || r.Db >= databases {
+		return 0, fmt.Errorf("invalid Redis.Db %d: must be between 0 and %d for a server with %d databases", r.Db, databases-1, databases)
+	}
+	return r.Db, nil
+}
+
+// Configured: Reports whether Redis has an address to connect to. In single
+// mode that needs Host and Port; leaving both empty means Redis is not used,
+// which only a Redis.Optional config accepts.
+
+func (r RedisConfig) Configured() bool {
+	switch r.Mode {
+	case "", RedisModeSingle:
+		return r.Host != "" || r.Port != ""
+	default:
+		return true
+	}
+}
+
+// validateAddr: Checks that Host and Port are set together, since Addr would
+// otherwise produce an address such as "localhost:" that only fails on dial.
+
+func (r RedisConfig) validateAddr() []error {
+	switch {
+	case r.Host != "" && r.Port == "":
+		return []error{fmt.Errorf("Redis.Host is set to %q but Redis.Port is empty; set both, or neither to run without Redis", r.Host)}
+	case r.Host == "" && r.Port != "":
+		return []error{fmt.Errorf("Redis.Port is set to %q but Redis.Host is empty; set both, or neither to run without Redis", r.Port)}
+	case !r.Configured() && !r.Optional:
+		return []error{errors.New("Redis.Host and Redis.Port are required unless Redis.Optional is set")}
+	}
+	return nil
+}
+
+// validateMode: Checks Mode and the addresses each mode needs.
+
+func (r RedisConfig) validateMode() []error {
+	switch r.Mode {
+	case "", RedisModeSingle:
+		return r.validateAddr()
+	case RedisModeSentinel:
+		var errs []error
+		if r.MasterName == "" {
+			errs = append(errs, errors.New("Redis.MasterName is required in sentinel mode"))
+		}
+		if len(r.SentinelAddrs) == 0 {
+			errs = append(errs, errors.New("Redis.SentinelAddrs needs at least one address in sentinel mode"))
+		}
+		return errs
+	case RedisModeCluster:
+		if len(r.ClusterAddrs) == 0 {
+			return []error{errors.New("Redis.ClusterAddrs needs at least one address in cluster mode")}
+		}
+		return nil
+	default:
+		return []error{oneOf("Redis.Mode", r.Mode, redisModes)}
+	}
+}
+
+// validateTimeouts: Rejects negative timeouts. A zero timeout is usually a
+// missing value rather than a decision, so it is replaced by its default with
+// a notice. Set AllowZeroTimeouts to keep zeros: a zero ReadTimeout or
+// WriteTimeout then means commands wait forever, and a zero DialTimeout,
+// PoolTimeout or IdleCheckFrequency leaves the go-redis default in place.
+
+func (r *RedisConfig) validateTimeouts() []error {
+	errs := nonNegative(map[string]time.Duration{
+		"Redis.DialTimeout":        r.DialTimeout,
+		"Redis.ReadTimeout":        r.ReadTimeout,
+		"Redis.WriteTimeout":       r.WriteTimeout,
+		"Redis.PoolTimeout":        r.PoolTimeout,
+		"Redis.IdleCheckFrequency": r.IdleCheckFrequency,
+	})
+	if r.AllowZeroTimeouts {
+		return errs
+	}
+	defaultIfZero(&r.DialTimeout, "Redis.DialTimeout", defaultRedisDialTimeout)
+	defaultIfZero(&r.ReadTimeout, "Redis.ReadTimeout", defaultRedisReadTimeout)
+	defaultIfZero(&r.WriteTimeout, "Redis.WriteTimeout", defaultRedisWriteTimeout)
+	defaultIfZero(&r.PoolTimeout, "Redis.PoolTimeout", defaultRedisPoolTimeout)
+	defaultIfZero(&r.IdleCheckFrequency, "Redis.IdleCheckFrequency", defaultRedisIdleCheckFrequency)
+	return errs
+}
+
+// defaultIfZero: Sets *field to def, logging a notice, when it is zero.
+
+func defaultIfZero(field *time.Duration, name string, def time.Duration) {
+	if *field == 0 {
+		log.Printf("config: %s is zero, defaulting to %s", name, def)
+		*field = def
+	}
+}
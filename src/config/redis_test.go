@@ -0,0 +1,209 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedisDbDecodesQuotedAndUnquoted(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want int
+	}{
+		{name: "unquoted", yaml: "redis:\n  db: 3\n", want: 3},
+		{name: "quoted", yaml: "redis:\n  db: \"3\"\n", want: 3},
+		{name: "absent", yaml: "redis:\n  host: localhost\n", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseYAML(t, tt.yaml)
+			if cfg.Redis.Db != tt.want {
+				t.Errorf("Redis.Db = %d, want %d", cfg.Redis.Db, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRedisDbRange(t *testing.T) {
+	for _, db := range []int{0, 15} {
+		cfg := validConfig(t)
+		cfg.Redis.Db = db
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Db %d: Validate: %v", db, err)
+		}
+	}
+	for _, db := range []int{-1, 16} {
+		cfg := validConfig(t)
+		cfg.Redis.Db = db
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid Redis.Db") {
+			t.Errorf("Db %d: Validate() = %v, want an invalid Redis.Db error", db, err)
+		}
+	}
+}
+
+func TestRedisDBIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		db        int
+		databases int
+		want      int
+		wantErr   string
+	}{
+		{name: "first", db: 0, want: 0},
+		{name: "last of the default 16", db: 15, want: 15},
+		{name: "past the default 16", db: 16, wantErr: "invalid Redis.Db 16: must be between 0 and 15 for a server with 16 databases"},
+		{name: "negative", db: -1, wantErr: "invalid Redis.Db -1: must be between 0 and 15"},
+		{name: "within a larger server", db: 31, databases: 32, want: 31},
+		{name: "past a larger server", db: 32, databases: 32, wantErr: "invalid Redis.Db 32: must be between 0 and 31 for a server with 32 databases"},
+		{name: "past a smaller server", db: 4, databases: 4, wantErr: "must be between 0 and 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RedisConfig{Db: tt.db, Databases: tt.databases}.DBIndex()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("DBIndex() = %d, %v, want an error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("DBIndex() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisDbRejectsNonNumeric(t *testing.T) {
+	for _, value := range []string{"two", `"two"`, `"db3"`} {
+		_, err := ParseConfigFromReader(strings.NewReader("redis:\n  db: "+value+"\n"), "yml")
+		if err == nil || !strings.Contains(err.Error(), "Redis.Db") {
+			t.Errorf("db: %s: ParseConfigFromReader error = %v, want one naming Redis.Db", value, err)
+		}
+	}
+}
+
+func TestValidateRedisDbAgainstDatabases(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Redis.Db, cfg.Redis.Databases = 20, 32
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Db 20 of 32: Validate: %v", err)
+	}
+	cfg.Redis.Databases = 8
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid Redis.Db 20") {
+		t.Errorf("Db 20 of 8: Validate() = %v, want an invalid Redis.Db error", err)
+	}
+}
+
+func TestValidateRedisMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      RedisConfig
+		wantErrs []string
+	}{
+		{name: "single", cfg: RedisConfig{Host: "cache", Port: "6379"}},
+		{name: "empty mode is single", cfg: RedisConfig{Mode: "", Host: "cache", Port: "6379"}},
+		{name: "sentinel", cfg: RedisConfig{Mode: RedisModeSentinel, MasterName: "mymaster", SentinelAddrs: []string{"s1:26379"}}},
+		{name: "sentinel without master or addresses", cfg: RedisConfig{Mode: RedisModeSentinel}, wantErrs: []string{
+			"Redis.MasterName is required in sentinel mode",
+			"Redis.SentinelAddrs needs at least one address in sentinel mode",
+		}},
+		{name: "cluster", cfg: RedisConfig{Mode: RedisModeCluster, ClusterAddrs: []string{"n1:6379"}}},
+		{name: "cluster without addresses", cfg: RedisConfig{Mode: RedisModeCluster}, wantErrs: []string{
+			"Redis.ClusterAddrs needs at least one address in cluster mode",
+		}},
+		{name: "unknown mode", cfg: RedisConfig{Mode: "ring"}, wantErrs: []string{`invalid Redis.Mode "ring"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.cfg.validateMode()
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("validateMode = %v, want %d errors", errs, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRedisTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      RedisConfig
+		wantRead time.Duration
+		wantDial time.Duration
+		wantErrs []string
+	}{
+		{name: "zeros get defaults", wantRead: defaultRedisReadTimeout, wantDial: defaultRedisDialTimeout},
+		{name: "configured values kept", cfg: RedisConfig{ReadTimeout: time.Second, DialTimeout: 2 * time.Second}, wantRead: time.Second, wantDial: 2 * time.Second},
+		{name: "zeros kept when allowed", cfg: RedisConfig{AllowZeroTimeouts: true}},
+		{name: "negative values rejected", cfg: RedisConfig{ReadTimeout: -time.Second, PoolTimeout: -time.Second}, wantRead: -time.Second, wantDial: defaultRedisDialTimeout, wantErrs: []string{
+			"invalid Redis.PoolTimeout -1s: must not be negative",
+			"invalid Redis.ReadTimeout -1s: must not be negative",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			errs := cfg.validateTimeouts()
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("validateTimeouts = %v, want %d errors", errs, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if errs[i].Error() != want {
+					t.Errorf("error %d = %q, want %q", i, errs[i], want)
+				}
+			}
+			if cfg.ReadTimeout != tt.wantRead || cfg.DialTimeout != tt.wantDial {
+				t.Errorf("ReadTimeout %s, DialTimeout %s; want %s, %s", cfg.ReadTimeout, cfg.DialTimeout, tt.wantRead, tt.wantDial)
+			}
+		})
+	}
+}
+
+func TestValidateRedisHostAndPort(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		port     string
+		optional bool
+		wantErr  string
+	}{
+		{name: "both", host: "cache", port: "6379"},
+		{name: "both, optional", host: "cache", port: "6379", optional: true},
+		{name: "host only", host: "cache", wantErr: `Redis.Host is set to "cache" but Redis.Port is empty`},
+		{name: "host only, optional", host: "cache", optional: true, wantErr: `Redis.Host is set to "cache" but Redis.Port is empty`},
+		{name: "port only", port: "6379", wantErr: `Redis.Port is set to "6379" but Redis.Host is empty`},
+		{name: "port only, optional", port: "6379", optional: true, wantErr: `Redis.Port is set to "6379" but Redis.Host is empty`},
+		{name: "neither", wantErr: "Redis.Host and Redis.Port are required unless Redis.Optional is set"},
+		{name: "neither, optional", optional: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Optional = tt.host, tt.port, tt.optional
+			if got, want := cfg.Redis.Configured(), tt.host != "" || tt.port != ""; got != want {
+				t.Errorf("Configured() = %v, want %v", got, want)
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisConfiguredInOtherModes(t *testing.T) {
+	for _, mode := range []string{RedisModeSentinel, RedisModeCluster} {
+		if !(RedisConfig{Mode: mode}).Configured() {
+			t.Errorf("a %s config without Host and Port is reported as not configured", mode)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Environment variables read by GetConfig when CONFIG_SOURCE=remote.
+const (
+	remoteProviderEnv = "CONFIG_REMOTE_PROVIDER"
+	remoteEndpointEnv = "CONFIG_REMOTE_ENDPOINT"
+	remotePathEnv     = "CONFIG_REMOTE_PATH"
+)
+
+// remoteTimeout bounds a single request to the KV store.
+const remoteTimeout = 10 * time.Second
+
+// viper only talks to KV stores through viper.RemoteConfig, which is normally
+// provided by the separate viper/remote module. kvRemote implements it for
+// Consul and etcd v3 over their HTTP APIs, so no extra client libraries are
+// needed.
+func init() {
+	if viper.RemoteConfig == nil {
+		viper.RemoteConfig = kvRemote{client: &http.Client{Timeout: remoteTimeout}}
+	}
+}
+
+// LoadRemoteConfig: Reads the config document stored under path in a Consul
+// ("consul") or etcd v3 ("etcd3") KV store at endpoint, e.g.
+// LoadRemoteConfig("consul", "http://consul:8500", "automart/config", "yml").
+// Defaults and environment overrides apply exactly as for file configs.
+
+func LoadRemoteConfig(provider, endpoint, path, fileType string) (*viper.Viper, error) {
+	v := newViper(fileType)
+	if err := v.AddRemoteProvider(provider, endpoint, path); err != nil {
+		return nil, err
+	}
+	if err := v.ReadRemoteConfig(); err != nil {
+		return nil, fmt.Errorf("read %s config %s from %s: %w", provider, path, endpoint, err)
+	}
+	return v, nil
+}
+
+// loadRemoteFromEnv: Calls LoadRemoteConfig with the provider, endpoint and
+// path named by the CONFIG_REMOTE_* variables, all of which must be set.
+
+func loadRemoteFromEnv() (*viper.Viper, error) {
+	provider := os.Getenv(remoteProviderEnv)
+	endpoint := os.Getenv(remoteEndpointEnv)
+	path := os.Getenv(remotePathEnv)
+	if provider == "" || endpoint == "" || path == "" {
+		return nil, fmt.Errorf("%s, %s and %s must all be set", remoteProviderEnv, remoteEndpointEnv, remotePathEnv)
+	}
+	return LoadRemoteConfig(provider, endpoint, path, "yml")
+}
+
+type kvRemote struct {
+	client *http.Client
+}
+
+func (r kvRemote) Get(rp viper.RemoteProvider) (io.Reader, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch rp.Provider() {
+	case "consul":
+		b, err = r.consul(rp.Endpoint(), rp.Path())
+	case "etcd3":
+		b, err = r.etcd3(rp.Endpoint(), rp.Path())
+	default:
+		return nil, fmt.Errorf("remote provider %q is not supported, use consul or etcd3", rp.Provider())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+func (r kvRemote) Watch(rp viper.RemoteProvider) (io.Reader, error) {
+	return r.Get(rp)
+}
+
+func (r kvRemote) WatchChannel(viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool) {
+	return nil, nil
+}
+
+// consul: Fetches the raw value of a key through the Consul KV HTTP API.
+
+func (r kvRemote) consul(endpoint, path string) ([]byte, error) {
+	resp, err := r.client.Get(baseURL(endpoint) + "/v1/kv/" + strings.TrimPrefix(path, "/") + "?raw")
+	if err != nil {
+		return nil, err
+	}
+	return readKVResponse(resp)
+}
+
+// etcd3: Fetches a key through the etcd v3 JSON gateway, which exchanges keys
+// and values base64-encoded.
+
+func (r kvRemote) etcd3(endpoint, path string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(path))})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Post(baseURL(endpoint)+"/v3/kv/range", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	b, err := readKVResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("decode etcd response: %w", err)
+	}
+	if len(out.Kvs) == 0 {
+		return nil, errors.New("key not found")
+	}
+	return base64.StdEncoding.DecodeString(out.Kvs[0].Value)
+}
+
+func readKVResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.New("key not found")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return b, nil
+}
+
+// baseURL: Accepts endpoints with or without a scheme, defaulting to http.
+
+func baseURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	return endpoint
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// kvServer serves doc under key like the Consul KV and etcd v3 gateway APIs.
+func kvServer(t *testing.T, key, doc string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/kv/", func(w http.ResponseWriter, r *http.Request) {
+		if _, raw := r.URL.Query()["raw"]; !raw || strings.TrimPrefix(r.URL.Path, "/v1/kv/") != key {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(doc))
+	})
+	mux.HandleFunc("/v3/kv/range", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Key string `json:"key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var kvs []map[string]string
+		if req.Key == base64.StdEncoding.EncodeToString([]byte(key)) {
+			kvs = append(kvs, map[string]string{"value": base64.StdEncoding.EncodeToString([]byte(doc))})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"kvs": kvs})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLoadRemoteConfig(t *testing.T) {
+	srv := kvServer(t, "automart/config", validYAML)
+	tests := []struct {
+		name     string
+		provider string
+		endpoint string
+		path     string
+		wantErr  string
+	}{
+		{name: "consul", provider: "consul", endpoint: srv.URL, path: "automart/config"},
+		{name: "etcd3", provider: "etcd3", endpoint: srv.URL, path: "automart/config"},
+		{name: "endpoint without scheme", provider: "consul", endpoint: strings.TrimPrefix(srv.URL, "http://"), path: "automart/config"},
+		{name: "missing key", provider: "consul", endpoint: srv.URL, path: "automart/other", wantErr: "read consul config automart/other"},
+		{name: "missing etcd key", provider: "etcd3", endpoint: srv.URL, path: "automart/other", wantErr: "read etcd3 config automart/other"},
+		{name: "unsupported provider", provider: "zookeeper", endpoint: srv.URL, path: "automart/config", wantErr: "zookeeper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := LoadRemoteConfig(tt.provider, tt.endpoint, tt.path, "yml")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadRemoteConfig error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadRemoteConfig: %v", err)
+			}
+			cfg, err := ParsConfig(v)
+			if err != nil {
+				t.Fatalf("ParsConfig: %v", err)
+			}
+			if cfg.Postgres.Host != "db.internal" || cfg.Redis.Host != "cache.internal" || cfg.Server.Port != "5005" {
+				t.Errorf("remote values not decoded: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestGetConfigRemote(t *testing.T) {
+	remoteDoc := strings.Replace(validYAML, "host: db.internal", "host: db.remote", 1)
+	srv := kvServer(t, "automart/config", remoteDoc)
+	tests := []struct {
+		name     string
+		endpoint string
+		path     string
+		wantHost string
+	}{
+		{name: "reads the KV store", endpoint: srv.URL, path: "automart/config", wantHost: "db.remote"},
+		{name: "falls back to the file when the key is missing", endpoint: srv.URL, path: "automart/other", wantHost: "db.internal"},
+		{name: "falls back to the file without an endpoint", path: "automart/config", wantHost: "db.internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useConfigDir(t, "staging")
+			writeFile(t, dir, "config-staging.yml", validYAML)
+			t.Setenv(configSourceEnv, "remote")
+			t.Setenv(remoteProviderEnv, "consul")
+			t.Setenv(remoteEndpointEnv, tt.endpoint)
+			t.Setenv(remotePathEnv, tt.path)
+
+			cfg, err := GetConfig()
+			if err != nil {
+				t.Fatalf("GetConfig: %v", err)
+			}
+			if cfg.Postgres.Host != tt.wantHost {
+				t.Errorf("Postgres.Host = %q, want %q", cfg.Postgres.Host, tt.wantHost)
+			}
+		})
+	}
+}
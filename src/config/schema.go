@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ExportJSONSchema: Returns a JSON Schema for the config files, generated by
+// reflection over Config so it never drifts from the struct. Keys use the
+// lowerCamel spelling of the field names (viper itself ignores case),
+// durations accept Go duration strings such as "15s" as well as numbers of
+// seconds, and fields tagged validate:"required" are listed as required.
+
+func ExportJSONSchema() ([]byte, error) {
+	r := &jsonschema.Reflector{
+		FieldNameTag:               "mapstructure",
+		KeyNamer:                   schemaKey,
+		RequiredFromJSONSchemaTags: true,
+		Mapper:                     schemaType,
+	}
+	schema := r.Reflect(&Config{})
+	annotateSchema(schema.Definitions, reflect.TypeOf(Config{}))
+	if def, ok := schema.Definitions["Config"]; ok {
+		// extends is read by LoadConfig, not decoded into Config.
+		def.Properties.Set(extendsKey, &jsonschema.Schema{Type: "string", Description: "profile whose config file this one is merged on top of"})
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaType: Maps types whose YAML form differs from their Go kind.
+
+func schemaType(t reflect.Type) *jsonschema.Schema {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return stringOrInteger()
+	}
+	return nil
+}
+
+func stringOrInteger() *jsonschema.Schema {
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{{Type: "string"}, {Type: "integer"}}}
+}
+
+// annotateSchema: Copies what the validate tags say into the definition of t
+// and the struct types below it: required fields become required keys, and
+// port fields, held as strings but usually written as numbers, accept both.
+// Sections holding required fields are required themselves. Fields tagged
+// validate:"-" (the optional replica) are left as reflected. It reports
+// whether t has any required key.
+
+func annotateSchema(defs jsonschema.Definitions, t reflect.Type) bool {
+	def, ok := defs[t.Name()]
+	if !ok {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		key := schemaKey(f.Name)
+		rules := strings.Split(tag, ",")
+		required := hasRule(rules, "required")
+		if hasRule(rules, "port") {
+			def.Properties.Set(key, stringOrInteger())
+		}
+		if f.Type.Kind() == reflect.Struct && annotateSchema(defs, f.Type) {
+			required = true
+		}
+		if required {
+			def.Required = append(def.Required, key)
+		}
+	}
+	return len(def.Required) > 0
+}
+
+func hasRule(rules []string, name string) bool {
+	for _, r := range rules {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaKey: Converts a Go field name to lowerCamel, keeping acronyms
+// together: "DbName" becomes "dbName", "SSLMode" "sslMode" and "HTTPClient"
+// "httpClient".
+
+func schemaKey(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		n-- // the last capital starts the next word
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// schemaProperty is the part of a JSON Schema property the tests look at.
+type schemaProperty struct {
+	Type  string           `json:"type"`
+	Ref   string           `json:"$ref"`
+	OneOf []schemaProperty `json:"oneOf"`
+}
+
+// typeName returns "string", "string|integer" or the referenced definition.
+func (p schemaProperty) typeName() string {
+	if p.Ref != "" {
+		return p.Ref
+	}
+	if len(p.OneOf) > 0 {
+		name := ""
+		for i, o := range p.OneOf {
+			if i > 0 {
+				name += "|"
+			}
+			name += o.typeName()
+		}
+		return name
+	}
+	return p.Type
+}
+
+type schemaDefinition struct {
+	Required   []string                  `json:"required"`
+	Properties map[string]schemaProperty `json:"properties"`
+}
+
+func TestExportJSONSchema(t *testing.T) {
+	b, err := ExportJSONSchema()
+	if err != nil {
+		t.Fatalf("ExportJSONSchema: %v", err)
+	}
+	var schema struct {
+		Ref  string                      `json:"$ref"`
+		Defs map[string]schemaDefinition `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	if schema.Ref != "#/$defs/Config" {
+		t.Errorf("$ref = %q, want the Config definition", schema.Ref)
+	}
+
+	tests := []struct {
+		def, key string
+		wantType string
+		required bool
+	}{
+		{def: "Config", key: "server", wantType: "#/$defs/ServerConfig", required: true},
+		{def: "Config", key: "postgres", wantType: "#/$defs/PostgresConfig", required: true},
+		{def: "Config", key: "postgresReplica", wantType: "#/$defs/PostgresReplicaConfig"},
+		{def: "Config", key: "extends", wantType: "string"},
+		{def: "ServerConfig", key: "port", wantType: "string|integer", required: true},
+		{def: "ServerConfig", key: "runMode", wantType: "string"},
+		{def: "ServerConfig", key: "shutdownTimeout", wantType: "string|integer"},
+		{def: "PostgresConfig", key: "host", wantType: "string", required: true},
+		{def: "PostgresConfig", key: "port", wantType: "string|integer", required: true},
+		{def: "PostgresConfig", key: "user", wantType: "string", required: true},
+		{def: "PostgresConfig", key: "dbName", wantType: "string", required: true},
+		{def: "PostgresConfig", key: "password", wantType: "string"},
+		{def: "PostgresConfig", key: "sslMode", wantType: "string"},
+		{def: "PostgresConfig", key: "maxOpenConns", wantType: "integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.def+"."+tt.key, func(t *testing.T) {
+			def, ok := schema.Defs[tt.def]
+			if !ok {
+				t.Fatalf("schema has no %s definition", tt.def)
+			}
+			prop, ok := def.Properties[tt.key]
+			if !ok {
+				t.Fatalf("%s has no property %q", tt.def, tt.key)
+			}
+			if got := prop.typeName(); got != tt.wantType {
+				t.Errorf("type = %q, want %q", got, tt.wantType)
+			}
+			if got := contains(def.Required, tt.key); got != tt.required {
+				t.Errorf("required = %v, want %v", got, tt.required)
+			}
+		})
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
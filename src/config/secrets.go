@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// secretFileSuffix: Following the Docker secrets convention,
+// AUTOMART_POSTGRES_PASSWORD_FILE=/run/secrets/pg sets Postgres.Password to
+// the contents of /run/secrets/pg.
+const secretFileSuffix = "_FILE"
+
+// secretKeys: Returns the viper key of every secret string field in Config,
+// as recognised by isSecretField.
+
+func secretKeys() []string {
+	return secretStructKeys(reflect.TypeOf(Config{}), "")
+}
+
+func secretStructKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		key := prefix + strings.ToLower(f.Name)
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			keys = append(keys, secretStructKeys(f.Type, key+".")...)
+		case f.Type.Kind() == reflect.String && isSecretField(f.Name):
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// applySecretFiles: For every secret key whose *_FILE variable is set, reads
+// the file and overrides the key with its contents, minus trailing
+// whitespace. The file wins over the plain variable, the config file and
+// command line flags.
+
+func applySecretFiles(v *viper.Viper) error {
+	replacer := strings.NewReplacer(".", "_")
+	for _, key := range secretKeys() {
+		name := envPrefix + "_" + strings.ToUpper(replacer.Replace(key)) + secretFileSuffix
+		path := os.Getenv(name)
+		if path == "" {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		v.Set(key, strings.TrimRight(string(b), " \t\r\n"))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	pgFile := writeFile(t, dir, "pg", "pg-from-file\n")
+	redisFile := writeFile(t, dir, "redis", "redis-from-file \t\r\n")
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantPG    string
+		wantRedis string
+		wantErr   string
+	}{
+		{name: "no files", wantPG: "pg-secret", wantRedis: "redis-secret"},
+		{
+			name:      "files override the config file, trimmed",
+			env:       map[string]string{"AUTOMART_POSTGRES_PASSWORD_FILE": pgFile, "AUTOMART_REDIS_PASSWORD_FILE": redisFile},
+			wantPG:    "pg-from-file",
+			wantRedis: "redis-from-file",
+		},
+		{
+			name:      "file wins over the plain variable",
+			env:       map[string]string{"AUTOMART_POSTGRES_PASSWORD_FILE": pgFile, "AUTOMART_POSTGRES_PASSWORD": "pg-from-env"},
+			wantPG:    "pg-from-file",
+			wantRedis: "redis-secret",
+		},
+		{
+			name:    "unreadable file",
+			env:     map[string]string{"AUTOMART_REDIS_PASSWORD_FILE": dir + "/missing"},
+			wantErr: "read AUTOMART_REDIS_PASSWORD_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := ParseConfigFromReader(strings.NewReader(validYAML), "yml")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseConfigFromReader error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Postgres.Password != tt.wantPG || cfg.Redis.Password != tt.wantRedis {
+				t.Errorf("passwords = %q, %q; want %q, %q", cfg.Postgres.Password, cfg.Redis.Password, tt.wantPG, tt.wantRedis)
+			}
+		})
+	}
+}
+
+func TestSecretKeys(t *testing.T) {
+	keys := strings.Join(secretKeys(), ",")
+	for _, want := range []string{"postgres.password", "redis.password", "auth.secretkey", "storage.secretkey", "mail.password"} {
+		if !strings.Contains(keys, want) {
+			t.Errorf("secretKeys() = %s, missing %s", keys, want)
+		}
+	}
+	if strings.Contains(keys, "postgres.host") {
+		t.Errorf("secretKeys() = %s includes a non-secret key", keys)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Address: Returns the address the HTTP server listens on. InternalPort (the
+// port inside the container) wins over Port when set; with no Host the
+// result is ":port", i.e. all interfaces.
+
+func (s ServerConfig) Address() string {
+	port := s.InternalPort
+	if port == "" {
+		port = s.Port
+	}
+	return net.JoinHostPort(s.Host, port)
+}
+
+// GinMode: Returns the gin mode matching RunMode, "debug" when it is empty.
+
+func (s ServerConfig) GinMode() string {
+	if s.RunMode == "" {
+		return "debug"
+	}
+	return s.RunMode
+}
+
+// PublicURL: Returns the base URL clients reach the server at, for links in
+// emails, OAuth redirects and image URLs: https with EnableTLS and http
+// otherwise, Domain (Host, then "localhost", when empty) and ExternalPort
+// (Port when empty). A wildcard bind Host such as 0.0.0.0 or :: is not an
+// address clients can use, so localhost is used instead. The port is left
+// out when it is the scheme default.
+// There is no trailing slash, so paths can be appended directly.
+
+func (s ServerConfig) PublicURL() string {
+	scheme, defaultPort := "http", "80"
+	if s.EnableTLS {
+		scheme, defaultPort = "https", "443"
+	}
+	host := s.Domain
+	if host == "" && !isWildcardHost(s.Host) {
+		host = s.Host
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	port := s.ExternalPort
+	if port == "" {
+		port = s.Port
+	}
+
+	u := url.URL{Scheme: scheme, Host: host}
+	if port != "" && port != defaultPort {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	return u.String()
+}
+
+// isWildcardHost: Reports whether host is empty or an unspecified address
+// that listens on every interface, such as 0.0.0.0 or ::.
+
+func isWildcardHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsUnspecified()
+}
+
+// validateTrustedProxies: Checks that every TrustedProxies entry is an IP
+// address or a CIDR range, as gin's SetTrustedProxies requires.
+
+func (s ServerConfig) validateTrustedProxies() []error {
+	var errs []error
+	for _, p := range s.TrustedProxies {
+		if net.ParseIP(p) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(p); err != nil {
+			errs = append(errs, fmt.Errorf("invalid Server.TrustedProxies entry %q: must be an IP address or CIDR range", p))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		want string
+	}{
+		{name: "port only", cfg: ServerConfig{Port: "5005"}, want: ":5005"},
+		{name: "host and port", cfg: ServerConfig{Host: "127.0.0.1", Port: "5005"}, want: "127.0.0.1:5005"},
+		{name: "internal port wins", cfg: ServerConfig{Host: "0.0.0.0", Port: "5005", InternalPort: "8080"}, want: "0.0.0.0:8080"},
+		{name: "internal port without host", cfg: ServerConfig{Port: "5005", InternalPort: "8080"}, want: ":8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Address(); got != tt.want {
+				t.Errorf("Address() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisAddr(t *testing.T) {
+	tests := []struct {
+		host, port, want string
+	}{
+		{host: "localhost", port: "6379", want: "localhost:6379"},
+		{host: "", port: "6379", want: ":6379"},
+		{host: "::1", port: "6379", want: "[::1]:6379"},
+	}
+	for _, tt := range tests {
+		if got := (RedisConfig{Host: tt.host, Port: tt.port}).Addr(); got != tt.want {
+			t.Errorf("Addr() for %q, %q = %q, want %q", tt.host, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestRunMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr string
+	}{
+		{mode: "debug", want: "debug"},
+		{mode: "release", want: "release"},
+		{mode: "test", want: "test"},
+		{mode: "", want: "debug"},
+		{mode: "prod", wantErr: `invalid Server.RunMode "prod": must be one of debug, release, test`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Server.RunMode = tt.mode
+			if tt.wantErr == "" && cfg.Server.GinMode() != tt.want {
+				t.Errorf("GinMode() = %q, want %q", cfg.Server.GinMode(), tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if cfg.Server.RunMode != tt.want {
+				t.Errorf("RunMode after Validate = %q, want %q", cfg.Server.RunMode, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	tests := []struct {
+		proxies []string
+		wantErr string
+	}{
+		{proxies: nil},
+		{proxies: []string{"10.0.0.1", "192.168.0.0/16", "::1", "fd00::/8"}},
+		{proxies: []string{"10.0.0.1", "load-balancer"}, wantErr: `invalid Server.TrustedProxies entry "load-balancer": must be an IP address or CIDR range`},
+		{proxies: []string{"10.0.0.0/33"}, wantErr: `invalid Server.TrustedProxies entry "10.0.0.0/33"`},
+	}
+	for _, tt := range tests {
+		cfg := validConfig(t)
+		cfg.Server.TrustedProxies = tt.proxies
+		err := cfg.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Validate() with %q: %v", tt.proxies, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate() with %q = %v, want an error containing %q", tt.proxies, err, tt.wantErr)
+		}
+	}
+}
+
+func TestServerPublicURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		want string
+	}{
+		{name: "http", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "8080"}, want: "http://automart.example:8080"},
+		{name: "https", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "8443", EnableTLS: true}, want: "https://automart.example:8443"},
+		{name: "http default port omitted", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "80"}, want: "http://automart.example"},
+		{name: "https default port omitted", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "443", EnableTLS: true}, want: "https://automart.example"},
+		{name: "443 kept without TLS", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "443"}, want: "http://automart.example:443"},
+		{name: "80 kept with TLS", cfg: ServerConfig{Domain: "automart.example", ExternalPort: "80", EnableTLS: true}, want: "https://automart.example:80"},
+		{name: "Port without ExternalPort", cfg: ServerConfig{Domain: "automart.example", Port: "5005"}, want: "http://automart.example:5005"},
+		{name: "ExternalPort wins over Port", cfg: ServerConfig{Domain: "automart.example", Port: "5005", ExternalPort: "443", EnableTLS: true}, want: "https://automart.example"},
+		{name: "Host without Domain", cfg: ServerConfig{Host: "10.0.0.5", Port: "5005"}, want: "http://10.0.0.5:5005"},
+		{name: "localhost without Domain and Host", cfg: ServerConfig{Port: "5005"}, want: "http://localhost:5005"},
+		{name: "localhost for the IPv4 wildcard Host", cfg: ServerConfig{Host: "0.0.0.0", Port: "5005"}, want: "http://localhost:5005"},
+		{name: "localhost for the IPv6 wildcard Host", cfg: ServerConfig{Host: "::", Port: "5005"}, want: "http://localhost:5005"},
+		{name: "localhost for a bracketed wildcard Host", cfg: ServerConfig{Host: "[::]", Port: "443", EnableTLS: true}, want: "https://localhost"},
+		{name: "Domain wins over a wildcard Host", cfg: ServerConfig{Host: "0.0.0.0", Port: "5005", Domain: "automart.example"}, want: "http://automart.example:5005"},
+		{name: "IPv6 with port", cfg: ServerConfig{Domain: "::1", ExternalPort: "8080"}, want: "http://[::1]:8080"},
+		{name: "IPv6 on the default port", cfg: ServerConfig{Domain: "::1", ExternalPort: "80"}, want: "http://[::1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.PublicURL(); got != tt.want {
+				t.Errorf("PublicURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateServerDomain(t *testing.T) {
+	tests := []struct {
+		domain  string
+		wantErr bool
+	}{
+		{domain: ""},
+		{domain: "automart.example"},
+		{domain: "localhost"},
+		{domain: "10.0.0.5"},
+		{domain: "https://automart.example", wantErr: true},
+		{domain: "automart.example/path", wantErr: true},
+		{domain: "auto mart", wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig(t)
+		cfg.Server.Domain = tt.domain
+		err := cfg.Validate()
+		if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "Server.Domain")) {
+			t.Errorf("Validate() with Domain %q = %v, want an error naming Server.Domain", tt.domain, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("Validate() with Domain %q: %v", tt.domain, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// structValidator runs the `validate` struct tags of Config. On top of the
+// built-in tags it registers:
+//   - "host": an IP address or a host name; unlike RFC 1123 an underscore is
+//     allowed so Docker container names such as postgres_container pass
+//   - "port": replaces the built-in rule, which only handles unsigned ints,
+//     with one that parses the string ports used throughout Config
+var structValidator = newStructValidator()
+
+var hostNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_-]{0,62})(\.[A-Za-z0-9_]([A-Za-z0-9_-]{0,62}))*$`)
+
+func newStructValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	_ = v.RegisterValidation("host", func(fl validator.FieldLevel) bool {
+		host := fl.Field().String()
+		return net.ParseIP(host) != nil || hostNamePattern.MatchString(host)
+	})
+	_ = v.RegisterValidation("port", func(fl validator.FieldLevel) bool {
+		_, err := ParsePort(fl.FieldName(), fl.Field().String())
+		return err == nil
+	})
+	return v
+}
+
+// validateTags: Runs the struct tag validation of s (a pointer to a config
+// struct) and translates each failure into a message naming the config key,
+// rooted at section: "" for Config itself, e.g. "PostgresReplica" for a
+// sub-struct validated on its own. Missing required fields are grouped into
+// a single error.
+
+func validateTags(s interface{}, section string) []error {
+	err := structValidator.Struct(s)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		if err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
+	var errs []error
+	var missing []string
+	for _, fe := range fieldErrs {
+		// Namespace is "<StructType>.<Field>..."; swap the type for the section.
+		_, key, _ := strings.Cut(fe.Namespace(), ".")
+		if section != "" {
+			key = section + "." + key
+		}
+		if fe.Tag() == "required" {
+			missing = append(missing, key)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("invalid %s %q: %s", key, fmt.Sprint(fe.Value()), tagMessage(fe)))
+	}
+	if len(missing) > 0 {
+		errs = append([]error{fmt.Errorf("missing required config fields: %s", strings.Join(missing, ", "))}, errs...)
+	}
+	return errs
+}
+
+func tagMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "port":
+		return "must be a port number between 1 and 65535"
+	case "host":
+		return "must be a host name or IP address"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param() + " long"
+	default:
+		return fmt.Sprintf("fails the %q rule", fe.Tag())
+	}
+}
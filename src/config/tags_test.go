@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(c *Config)
+		wantErrs []string
+	}{
+		{
+			name: "docker container host names pass",
+			modify: func(c *Config) {
+				c.Postgres.Host = "postgres_container"
+				c.Redis.Host = "redis-container"
+			},
+		},
+		{
+			name: "several violations are listed together",
+			modify: func(c *Config) {
+				c.Postgres.Host = "db host!"
+				c.Postgres.Port = "99999"
+				c.Server.Domain = "exa mple.com"
+				c.Image.JPEGQuality = 101
+			},
+			wantErrs: []string{
+				`invalid Postgres.Host "db host!": must be a host name or IP address`,
+				`invalid Postgres.Port "99999": must be a port number between 1 and 65535`,
+				`invalid Server.Domain "exa mple.com": must be a host name or IP address`,
+				`invalid Image.JPEGQuality "101": must be at most 100`,
+			},
+		},
+		{
+			name:     "negative limits",
+			modify:   func(c *Config) { c.Image.MaxWidth = -1 },
+			wantErrs: []string{`invalid Image.MaxWidth "-1": must be at least 0`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+
+			errs := validateTags(cfg, "")
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("validateTags = %v, want %d errors", errs, len(tt.wantErrs))
+			}
+			joined := cfg.Validate()
+			for _, want := range tt.wantErrs {
+				if joined == nil || !strings.Contains(joined.Error(), want) {
+					t.Errorf("Validate() = %v, want an error containing %q", joined, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTagsSection(t *testing.T) {
+	replica := PostgresConfig{Host: "replica", Port: "0"}
+	errs := validateTags(&replica, "PostgresReplica")
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"missing required config fields: PostgresReplica.User, PostgresReplica.DbName",
+		`invalid PostgresReplica.Port "0": must be a port number between 1 and 65535`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("validateTags = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,12 @@
+package config
+
+import "fmt"
+
+// ValidateSampleRatio: Checks that SampleRatio is a fraction between 0 and 1.
+
+func (t TracingConfig) ValidateSampleRatio() error {
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("invalid Tracing.SampleRatio %v: must be between 0 and 1", t.SampleRatio)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestValidateSampleRatio(t *testing.T) {
+	tests := []struct {
+		ratio   float64
+		wantErr bool
+	}{
+		{ratio: 0},
+		{ratio: 0.25},
+		{ratio: 1},
+		{ratio: -0.01, wantErr: true},
+		{ratio: 1.01, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := TracingConfig{SampleRatio: tt.ratio}.ValidateSampleRatio()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSampleRatio(%v) = %v, want error: %v", tt.ratio, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logLevels and logEncodings are the values accepted by zap.
+var (
+	logLevels    = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+	logEncodings = []string{"json", "console"}
+)
+
+// runModes are the modes gin can run in.
+var runModes = []string{"debug", "release", "test"}
+
+// imageFormats are the upload formats ProcessImage can decode and re-encode.
+var imageFormats = []string{"jpeg", "jpg", "png", "gif"}
+
+// sslModes are the sslmode values understood by libpq and pgx.
+var sslModes = []string{"disable", "require", "verify-ca", "verify-full", "prefer", "allow"}
+
+// Validate: Checks the parsed Config for problems that would otherwise only
+// show up at runtime. Every problem found is reported in the returned error,
+// rather than stopping at the first one. Optional fields left empty are
+// filled with their fallback value and a warning is logged.
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, validateTags(c, "")...)
+	if c.PostgresReplica.Configured() {
+		replica := PostgresConfig(c.PostgresReplica)
+		errs = append(errs, validateTags(&replica, "PostgresReplica")...)
+	}
+
+	defaultIfEmpty(&c.Postgres.SSLMode, "Postgres.SSLMode", "disable")
+	if err := oneOf("Postgres.SSLMode", c.Postgres.SSLMode, sslModes); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, c.Server.validatePorts()...)
+	errs = append(errs, c.Server.validateTrustedProxies()...)
+
+	defaultIfEmpty(&c.Server.RunMode, "Server.RunMode", "debug")
+	if err := oneOf("Server.RunMode", c.Server.RunMode, runModes); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"Postgres.ConnMaxIdleTime":    c.Postgres.ConnMaxIdleTime,
+		"Postgres.SlowQueryThreshold": c.Postgres.SlowQueryThreshold,
+		"Postgres.ProxyIdleTimeout":   c.Postgres.ProxyIdleTimeout,
+	})...)
+	proxyChecks := []error{c.Postgres.checkProxyIdleTimeout("Postgres")}
+	if c.PostgresReplica.Configured() {
+		proxyChecks = append(proxyChecks, PostgresConfig(c.PostgresReplica).checkProxyIdleTimeout("PostgresReplica"))
+	}
+	for _, err := range proxyChecks {
+		switch {
+		case err == nil:
+		case strictConfig():
+			errs = append(errs, err)
+		default:
+			log.Printf("config: %v; set STRICT_CONFIG=true to make this an error", err)
+		}
+	}
+
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"Server.ShutdownTimeout":      c.Server.ShutdownTimeout,
+		"Server.ReadTimeout":          c.Server.ReadTimeout,
+		"Server.WriteTimeout":         c.Server.WriteTimeout,
+		"Server.IdleTimeout":          c.Server.IdleTimeout,
+		"Server.ReadHeaderTimeout":    c.Server.ReadHeaderTimeout,
+		"Server.SlowRequestThreshold": c.Server.SlowRequestThreshold,
+	})...)
+
+	if c.Server.EnableTLS {
+		errs = append(errs, c.Server.validateTLS()...)
+	}
+
+	if c.Cors.AllowCredentials && slices.Contains(c.Cors.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("invalid Cors: AllowCredentials cannot be combined with the \"*\" origin; list the allowed origins explicitly"))
+	}
+
+	if c.RateLimit.Enabled && c.RateLimit.RequestsPerMinute <= 0 {
+		errs = append(errs, fmt.Errorf("invalid RateLimit.RequestsPerMinute %d: must be positive when RateLimit.Enabled is set", c.RateLimit.RequestsPerMinute))
+	}
+	if c.RateLimit.Burst < 0 {
+		errs = append(errs, fmt.Errorf("invalid RateLimit.Burst %d: must not be negative", c.RateLimit.Burst))
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		if (errors.Is(err, errShortAuthSecret) || errors.Is(err, errPlaceholderAuthSecret)) && !c.IsProduction() {
+			log.Printf("config: %v; allowed outside production only", err)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"Auth.AccessTokenExpiry":  c.Auth.AccessTokenExpiry,
+		"Auth.RefreshTokenExpiry": c.Auth.RefreshTokenExpiry,
+	})...)
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		errs = append(errs, fmt.Errorf("invalid Metrics.Path %q: must start with /", c.Metrics.Path))
+	}
+	if c.Docs.Enabled && !strings.HasPrefix(c.Docs.Path, "/") {
+		errs = append(errs, fmt.Errorf("invalid Docs.Path %q: must start with /", c.Docs.Path))
+	}
+	for _, p := range []struct{ name, path string }{
+		{"Health.LivenessPath", c.Health.LivenessPath},
+		{"Health.ReadinessPath", c.Health.ReadinessPath},
+	} {
+		if !strings.HasPrefix(p.path, "/") {
+			errs = append(errs, fmt.Errorf("invalid %s %q: must start with /", p.name, p.path))
+		}
+	}
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"Health.ReadinessTimeout": c.Health.ReadinessTimeout,
+	})...)
+	if c.Static.Enabled {
+		errs = append(errs, c.Static.validate()...)
+	}
+	if !slices.Contains(c.I18n.SupportedLocales, c.I18n.DefaultLocale) {
+		errs = append(errs, fmt.Errorf("invalid I18n.DefaultLocale %q: must be one of I18n.SupportedLocales (%s)", c.I18n.DefaultLocale, strings.Join(c.I18n.SupportedLocales, ", ")))
+	}
+	if c.Search.Enabled && len(c.Search.Addresses) == 0 {
+		errs = append(errs, errors.New("Search.Addresses needs at least one address when Search.Enabled is set"))
+	}
+	errs = append(errs, c.AMQP.validate()...)
+	errs = append(errs, c.Pricing.validate()...)
+	for _, f := range c.Image.AllowedFormats {
+		if err := oneOf("Image.AllowedFormats entry", strings.ToLower(f), imageFormats); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.Metrics.Enabled && c.Metrics.StatsInterval <= 0 {
+		errs = append(errs, fmt.Errorf("invalid Metrics.StatsInterval %s: must be positive", c.Metrics.StatsInterval))
+	}
+
+	if err := c.Tracing.ValidateSampleRatio(); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, c.Redis.validateMode()...)
+	if _, err := c.Redis.DBIndex(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, c.Redis.validateTimeouts()...)
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"Redis.DefaultTTL":        c.Redis.DefaultTTL,
+		"Redis.ConnectRetryDelay": c.Redis.ConnectRetryDelay,
+	})...)
+
+	errs = append(errs, nonNegative(map[string]time.Duration{
+		"HTTPClient.Timeout":         c.HTTPClient.Timeout,
+		"HTTPClient.IdleConnTimeout": c.HTTPClient.IdleConnTimeout,
+	})...)
+
+	if err := c.Pagination.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	defaultIfEmpty(&c.Logger.Level, "Logger.Level", c.defaultLogLevel())
+	if err := oneOf("Logger.Level", c.Logger.Level, logLevels); err != nil {
+		errs = append(errs, err)
+	}
+	defaultIfEmpty(&c.Logger.Encoding, "Logger.Encoding", "json")
+	if err := oneOf("Logger.Encoding", c.Logger.Encoding, logEncodings); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Logger.StacktraceLevel != "" {
+		if err := oneOf("Logger.StacktraceLevel", c.Logger.StacktraceLevel, logLevels); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Logger.Levels)) {
+		if err := oneOf("Logger.Levels."+name, c.Logger.Levels[name], logLevels); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.Logger.SamplingInitial < 0 || c.Logger.SamplingThereafter < 0 {
+		errs = append(errs, fmt.Errorf("invalid Logger sampling %d/%d: SamplingInitial and SamplingThereafter must not be negative", c.Logger.SamplingInitial, c.Logger.SamplingThereafter))
+	}
+
+	return errors.Join(errs...)
+}
+
+// strictConfig: Reports whether STRICT_CONFIG is true, which turns the
+// validation warnings that can break things at runtime into errors.
+
+func strictConfig() bool {
+	strict, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("STRICT_CONFIG")))
+	return strict
+}
+
+// defaultIfEmpty: Sets *field to def, logging a notice, when it is empty.
+
+func defaultIfEmpty(field *string, name, def string) {
+	if *field == "" {
+		log.Printf("config: %s is empty, defaulting to %q", name, def)
+		*field = def
+	}
+}
+
+// oneOf: Rejects a value that is not in the allowed set, naming the field,
+// the bad value and the permitted values.
+
+func oneOf(name, value string, allowed []string) error {
+	if !slices.Contains(allowed, value) {
+		return fmt.Errorf("invalid %s %q: must be one of %s", name, value, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// nonNegative: Returns an error for every duration below zero, in name order.
+
+func nonNegative(durations map[string]time.Duration) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(durations)) {
+		if d := durations[name]; d < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %s: must not be negative", name, d))
+		}
+	}
+	return errs
+}
+
+// validateTLS: Checks that the certificate and key are configured and exist.
+
+func (s ServerConfig) validateTLS() []error {
+	var errs []error
+	for _, f := range []struct{ name, path string }{
+		{"Server.TLSCertFile", s.TLSCertFile},
+		{"Server.TLSKeyFile", s.TLSKeyFile},
+	} {
+		switch {
+		case f.path == "":
+			errs = append(errs, fmt.Errorf("%s is required when Server.EnableTLS is set", f.name))
+		case !fileExists(f.path):
+			errs = append(errs, fmt.Errorf("%s %s does not exist", f.name, f.path))
+		}
+	}
+	return errs
+}
+
+// validate: Checks that the static directory exists and the prefix is a path.
+
+func (s StaticConfig) validate() []error {
+	var errs []error
+	switch {
+	case s.Dir == "":
+		errs = append(errs, errors.New("Static.Dir is required when Static.Enabled is set"))
+	case !dirExists(s.Dir):
+		errs = append(errs, fmt.Errorf("Static.Dir %s does not exist or is not a directory", s.Dir))
+	}
+	if !strings.HasPrefix(s.URLPrefix, "/") {
+		errs = append(errs, fmt.Errorf("invalid Static.URLPrefix %q: must start with /", s.URLPrefix))
+	}
+	return errs
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a Config parsed from validYAML.
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := parseYAML(t, validYAML)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validYAML does not validate: %v", err)
+	}
+	return cfg
+}
+
+func TestValidateReportsAllMissingFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		clear   func(c *Config)
+		missing string
+	}{
+		{
+			name:    "postgres host",
+			clear:   func(c *Config) { c.Postgres.Host = "" },
+			missing: "Postgres.Host",
+		},
+		{
+			name: "postgres user and database",
+			clear: func(c *Config) {
+				c.Postgres.User = ""
+				c.Postgres.DbName = ""
+			},
+			missing: "Postgres.User, Postgres.DbName",
+		},
+		{
+			name: "every required field",
+			clear: func(c *Config) {
+				c.Server.Port = ""
+				c.Postgres.Host = ""
+				c.Postgres.Port = ""
+				c.Postgres.User = ""
+				c.Postgres.DbName = ""
+			},
+			missing: "Server.Port, Postgres.Host, Postgres.Port, Postgres.User, Postgres.DbName",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.clear(cfg)
+
+			err := cfg.Validate()
+			want := "missing required config fields: " + tt.missing
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, want)
+			}
+		})
+	}
+}
+
+func TestValidateSSLMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr string
+	}{
+		{mode: "verify-full", want: "verify-full"},
+		{mode: "prefer", want: "prefer"},
+		{mode: "", want: "disable"},
+		{mode: "requrie", wantErr: `invalid Postgres.SSLMode "requrie": must be one of disable, require, verify-ca, verify-full, prefer, allow`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Postgres.SSLMode = tt.mode
+
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if cfg.Postgres.SSLMode != tt.want {
+				t.Errorf("SSLMode = %q, want %q", cfg.Postgres.SSLMode, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLogger(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          string
+		level        string
+		encoding     string
+		wantLevel    string
+		wantEncoding string
+		wantErr      string
+	}{
+		{name: "valid", level: "warn", encoding: "console", wantLevel: "warn", wantEncoding: "console"},
+		{name: "empty in production", env: "production", wantLevel: "info", wantEncoding: "json"},
+		{name: "empty in development", env: "development", wantLevel: "debug", wantEncoding: "json"},
+		{name: "unknown level", level: "verbose", encoding: "json", wantErr: `invalid Logger.Level "verbose"`},
+		{name: "unknown encoding", level: "info", encoding: "text", wantErr: `invalid Logger.Encoding "text": must be one of json, console`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Env = tt.env
+			cfg.Logger.Level = tt.level
+			cfg.Logger.Encoding = tt.encoding
+
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if cfg.Logger.Level != tt.wantLevel || cfg.Logger.Encoding != tt.wantEncoding {
+				t.Errorf("Logger = %q/%q, want %q/%q", cfg.Logger.Level, cfg.Logger.Encoding, tt.wantLevel, tt.wantEncoding)
+			}
+		})
+	}
+}
+
+func TestValidateServerTimeouts(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Server.ReadTimeout = -time.Second
+	cfg.Server.IdleTimeout = -time.Minute
+	cfg.Server.SlowRequestThreshold = -time.Millisecond
+
+	err := cfg.Validate()
+	for _, want := range []string{"invalid Server.ReadTimeout -1s", "invalid Server.IdleTimeout -1m0s", "invalid Server.SlowRequestThreshold -1ms"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want an error containing %q", err, want)
+		}
+	}
+}
+
+func TestValidateTLS(t *testing.T) {
+	dir := t.TempDir()
+	cert := writeFile(t, dir, "cert.pem", "cert")
+	key := writeFile(t, dir, "key.pem", "key")
+	tests := []struct {
+		name      string
+		cert, key string
+		wantErrs  []string
+	}{
+		{name: "both files exist", cert: cert, key: key},
+		{name: "missing paths", wantErrs: []string{
+			"Server.TLSCertFile is required when Server.EnableTLS is set",
+			"Server.TLSKeyFile is required when Server.EnableTLS is set",
+		}},
+		{name: "missing key file", cert: cert, key: dir + "/nope.pem", wantErrs: []string{
+			"Server.TLSKeyFile " + dir + "/nope.pem does not exist",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Server.EnableTLS = true
+			cfg.Server.TLSCertFile = tt.cert
+			cfg.Server.TLSKeyFile = tt.key
+
+			err := cfg.Validate()
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			for _, want := range tt.wantErrs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %v, want an error containing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCorsCredentialsWithWildcard(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     bool
+	}{
+		{name: "wildcard without credentials", origins: []string{"*"}},
+		{name: "explicit origin with credentials", origins: []string{"https://app.automart.test"}, credentials: true},
+		{name: "wildcard with credentials", origins: []string{"https://app.automart.test", "*"}, credentials: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Cors.AllowedOrigins = tt.origins
+			cfg.Cors.AllowCredentials = tt.credentials
+
+			err := cfg.Validate()
+			if got := err != nil && strings.Contains(err.Error(), "AllowCredentials cannot be combined"); got != tt.wantErr {
+				t.Errorf("Validate() = %v, want the wildcard conflict reported: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLoggerOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(l *LoggerConfig)
+		wantErr string
+	}{
+		{name: "stacktrace level", change: func(l *LoggerConfig) { l.StacktraceLevel = "error" }},
+		{name: "sampling", change: func(l *LoggerConfig) { l.SamplingInitial, l.SamplingThereafter = 100, 10 }},
+		{name: "unknown stacktrace level", change: func(l *LoggerConfig) { l.StacktraceLevel = "loud" }, wantErr: `invalid Logger.StacktraceLevel "loud"`},
+		{name: "negative sampling", change: func(l *LoggerConfig) { l.SamplingThereafter = -1 }, wantErr: "invalid Logger sampling 0/-1"},
+		{name: "named levels", change: func(l *LoggerConfig) { l.Levels = map[string]string{"gorm": "warn", "http": "info"} }},
+		{name: "unknown named level", change: func(l *LoggerConfig) { l.Levels = map[string]string{"gorm": "chatty"} }, wantErr: `invalid Logger.Levels.gorm "chatty"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.change(&cfg.Logger)
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateStatic(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "image.jpg", "jpeg")
+	tests := []struct {
+		name    string
+		static  StaticConfig
+		wantErr string
+	}{
+		{name: "disabled needs nothing", static: StaticConfig{}},
+		{name: "existing directory", static: StaticConfig{Enabled: true, Dir: dir, URLPrefix: "/static"}},
+		{name: "missing dir", static: StaticConfig{Enabled: true, URLPrefix: "/static"}, wantErr: "Static.Dir is required when Static.Enabled is set"},
+		{name: "dir does not exist", static: StaticConfig{Enabled: true, Dir: dir + "/missing", URLPrefix: "/static"}, wantErr: "does not exist or is not a directory"},
+		{name: "dir is a file", static: StaticConfig{Enabled: true, Dir: file, URLPrefix: "/static"}, wantErr: "does not exist or is not a directory"},
+		{name: "relative prefix", static: StaticConfig{Enabled: true, Dir: dir, URLPrefix: "static"}, wantErr: `invalid Static.URLPrefix "static": must start with /`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Static = tt.static
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMaxRequestBodyBytes(t *testing.T) {
+	tests := []struct {
+		limit   int64
+		wantErr bool
+	}{
+		{limit: 0},
+		{limit: 10 << 20},
+		{limit: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig(t)
+		cfg.Server.MaxRequestBodyBytes = tt.limit
+		err := cfg.Validate()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("Validate() with MaxRequestBodyBytes %d = %v, want error %v", tt.limit, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "MaxRequestBodyBytes") {
+			t.Errorf("error %q does not name MaxRequestBodyBytes", err)
+		}
+	}
+}
+
+func TestValidateI18n(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultLocale string
+		supported     []string
+		wantErr       string
+	}{
+		{name: "default is supported", defaultLocale: "fa", supported: []string{"en", "fa"}},
+		{name: "default not supported", defaultLocale: "de", supported: []string{"en", "fa"}, wantErr: `invalid I18n.DefaultLocale "de": must be one of I18n.SupportedLocales (en, fa)`},
+		{name: "no supported locales", defaultLocale: "en", wantErr: `invalid I18n.DefaultLocale "en"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.I18n.DefaultLocale = tt.defaultLocale
+			cfg.I18n.SupportedLocales = tt.supported
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   ImageConfig
+		wantErr string
+	}{
+		{name: "known formats in any case", image: ImageConfig{AllowedFormats: []string{"JPG", "png", "gif"}, JPEGQuality: 80}},
+		{name: "unknown format", image: ImageConfig{AllowedFormats: []string{"png", "bmp"}}, wantErr: `invalid Image.AllowedFormats entry "bmp"`},
+		{name: "quality over 100", image: ImageConfig{JPEGQuality: 101}, wantErr: "JPEGQuality"},
+		{name: "negative MaxPixels", image: ImageConfig{MaxPixels: -1}, wantErr: "MaxPixels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Image = tt.image
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
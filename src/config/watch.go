@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/pflag"
+)
+
+// Watch: Reloads the configuration whenever its file is written. Each
+// reload goes through LoadConfig again (so config-base is re-merged), has
+// the command line flags and unknown-key check of GetConfig applied again,
+// is parsed into a new Config and validated; onChange is called with it
+// only when validation passes, otherwise the previous Config stays in effect.
+// Accepted reloads are logged together with the fields that changed.
+// Configs are never modified in place: if c is the instance served by Get,
+// Get starts returning the reloaded Config instead.
+
+func (c *Config) Watch(onChange func(*Config)) {
+	if c.v == nil || c.v.ConfigFileUsed() == "" {
+		log.Printf("config: Watch needs a Config loaded from a file, hot reload disabled")
+		return
+	}
+
+	r := c.sharedReloader(onChange)
+	c.v.OnConfigChange(func(fsnotify.Event) { r.reload() })
+	c.v.WatchConfig()
+}
+
+// ReloadOnSignal: Reloads the configuration every time the process receives
+// sig (usually syscall.SIGHUP), with the same load, validate and swap cycle
+// as Watch. It returns immediately; the listener stops when ctx is done.
+// Watch and ReloadOnSignal may both be used on the same Config: they share
+// one reloader, so each reload starts from the newest Config and calls
+// every registered onChange.
+
+func (c *Config) ReloadOnSignal(ctx context.Context, sig os.Signal, onChange func(*Config)) {
+	if c.v == nil || c.v.ConfigFileUsed() == "" {
+		log.Printf("config: ReloadOnSignal needs a Config loaded from a file, reload on %s disabled", sig)
+		return
+	}
+
+	r := c.sharedReloader(onChange)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				log.Printf("config: received %s", sig)
+				r.reload()
+			}
+		}
+	}()
+}
+
+// reloadersMu guards Config.reloads.
+var reloadersMu sync.Mutex
+
+// reloader re-reads one config file and swaps the result in. It is shared by
+// every Watch and ReloadOnSignal of a Config and of the Configs reloaded
+// from it, so file events and signals, which arrive on different
+// goroutines, take turns through mu.
+type reloader struct {
+	path  string
+	flags *pflag.FlagSet
+
+	mu        sync.Mutex
+	current   *Config
+	callbacks []func(*Config)
+}
+
+// sharedReloader: Returns the reloader of c, creating it on first use, with
+// onChange added to its callbacks when not nil.
+
+func (c *Config) sharedReloader(onChange func(*Config)) *reloader {
+	reloadersMu.Lock()
+	if c.reloads == nil {
+		c.reloads = &reloader{path: c.v.ConfigFileUsed(), flags: c.flags, current: c}
+	}
+	r := c.reloads
+	reloadersMu.Unlock()
+
+	if onChange != nil {
+		r.mu.Lock()
+		r.callbacks = append(r.callbacks, onChange)
+		r.mu.Unlock()
+	}
+	return r
+}
+
+// latest: Returns the newest Config reloaded from c by Watch or
+// ReloadOnSignal, or c itself when it was never reloaded.
+
+func (c *Config) latest() *Config {
+	reloadersMu.Lock()
+	r := c.reloads
+	reloadersMu.Unlock()
+	if r == nil {
+		return c
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+func (r *reloader) reload() {
+	next, callbacks := r.swap()
+	for _, onChange := range callbacks {
+		// A clone, so the callback cannot modify the Config Get hands out.
+		onChange(next.Clone())
+	}
+}
+
+// swap: Loads the file, applies the command line flags and checks unknown
+// keys as GetConfig does (see prepareViper), validates the result and makes
+// it current. It returns nil callbacks when the reload is rejected.
+
+func (r *reloader) swap() (*Config, []func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *Config
+	v, err := reloadConfigFile(r.path)
+	if err == nil {
+		err = prepareViper(v, r.flags)
+	}
+	if err == nil {
+		next, err = ParsConfig(v)
+	}
+	if err == nil {
+		next.Env = r.current.Env
+		next.flags = r.flags
+		err = next.Validate()
+	}
+	if err != nil {
+		log.Printf("config: reload of %s rejected, keeping previous config: %v", r.path, err)
+		return nil, nil
+	}
+
+	next.reloads = r
+
+	previous := r.current
+	instanceMu.Lock()
+	if instance == r.current {
+		instance = next
+	}
+	r.current = next
+	instanceMu.Unlock()
+
+	log.Printf("config: reloaded %s: %s", r.path, describeChanges(previous.Diff(next)))
+	return next, slices.Clone(r.callbacks)
+}
+
+func describeChanges(changes []string) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config-staging.yml", validYAML)
+	cfg := loadDir(t, dir)
+	cfg.Env = "staging"
+
+	changes := make(chan *Config, 16)
+	cfg.Watch(func(next *Config) { changes <- next })
+
+	steps := []struct {
+		name     string
+		content  string
+		wantPort string // empty when the reload must be rejected
+	}{
+		{name: "valid rewrite", content: strings.Replace(validYAML, "port: 5005", "port: 6006", 1), wantPort: "6006"},
+		{name: "invalid rewrite is rejected", content: strings.Replace(validYAML, "host: db.internal", "host: ''", 1)},
+		{name: "recovers after a rejected rewrite", content: strings.Replace(validYAML, "port: 5005", "port: 7007", 1), wantPort: "7007"},
+	}
+	for _, step := range steps {
+		writeFile(t, dir, "config-staging.yml", step.content)
+		if step.wantPort == "" {
+			// Late events from the previous write may still arrive, but
+			// never with the rejected values.
+			time.Sleep(300 * time.Millisecond)
+			for len(changes) > 0 {
+				if next := <-changes; next.Postgres.Host == "" {
+					t.Fatalf("%s: onChange called with the invalid config", step.name)
+				}
+			}
+			if got := cfg.latest().Postgres.Host; got != "db.internal" {
+				t.Errorf("%s: latest Postgres.Host = %q, want the previous db.internal", step.name, got)
+			}
+			continue
+		}
+		next := waitForPort(t, changes, step.wantPort)
+		if next == nil {
+			t.Fatalf("%s: onChange not called with Server.Port %s after rewriting %s", step.name, step.wantPort, path)
+		}
+		if got := cfg.latest().Server.Port; got != step.wantPort {
+			t.Errorf("%s: latest Server.Port = %q, want %q", step.name, got, step.wantPort)
+		}
+		next.Server.Port = "1"
+		if got := cfg.latest().Server.Port; got != step.wantPort {
+			t.Errorf("%s: changing the Config passed to onChange changed the latest one to %q", step.name, got)
+		}
+	}
+}
+
+// waitForPort drains changes until a Config with Server.Port port arrives,
+// since one write may produce several file events.
+func waitForPort(t *testing.T, changes <-chan *Config, port string) *Config {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case next := <-changes:
+			if next.Server.Port == port {
+				return next
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	// Keeps a SIGHUP that arrives after the listener stopped from
+	// terminating the test binary.
+	guard := make(chan os.Signal, 16)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "config-staging.yml", validYAML)
+	cfg := loadDir(t, dir)
+	cfg.Env = "staging"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan *Config, 16)
+	cfg.ReloadOnSignal(ctx, syscall.SIGHUP, func(next *Config) { changes <- next })
+
+	steps := []struct {
+		name     string
+		content  string
+		stop     bool
+		wantPort string // empty when no callback may fire
+	}{
+		{name: "reloads on the signal", content: strings.Replace(validYAML, "port: 5005", "port: 6006", 1), wantPort: "6006"},
+		{name: "invalid file is rejected", content: strings.Replace(validYAML, "host: db.internal", "host: ''", 1)},
+		{name: "recovers after a rejected file", content: strings.Replace(validYAML, "port: 5005", "port: 7007", 1), wantPort: "7007"},
+		{name: "stops when ctx is cancelled", content: strings.Replace(validYAML, "port: 5005", "port: 8008", 1), stop: true},
+	}
+	for _, step := range steps {
+		writeFile(t, dir, "config-staging.yml", step.content)
+		if step.stop {
+			cancel()
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatal(err)
+		}
+		if step.wantPort == "" {
+			select {
+			case next := <-changes:
+				t.Fatalf("%s: onChange called with Server.Port %s", step.name, next.Server.Port)
+			case <-time.After(300 * time.Millisecond):
+			}
+			continue
+		}
+		next := waitForPort(t, changes, step.wantPort)
+		if next == nil {
+			t.Fatalf("%s: onChange not called with Server.Port %s", step.name, step.wantPort)
+		}
+		if got := cfg.latest().Server.Port; got != step.wantPort {
+			t.Errorf("%s: latest Server.Port = %q, want %q", step.name, got, step.wantPort)
+		}
+	}
+	if got := cfg.latest().Server.Port; got != "7007" {
+		t.Errorf("Server.Port after cancel = %q, want 7007", got)
+	}
+}
+
+func TestReloadOnSignalNeedsAFile(t *testing.T) {
+	cfg := parseYAML(t, validYAML)
+	// Returns without listening; a SIGHUP now would terminate the test.
+	cfg.ReloadOnSignal(context.Background(), syscall.SIGHUP, func(*Config) {
+		t.Error("onChange called for a Config without a file")
+	})
+	if cfg.reloads != nil {
+		t.Error("reloader created for a Config without a file")
+	}
+}
+
+func TestReloadKeepsFlagsAndChecksKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		strict   string
+		wantHost string // empty when the reload must be rejected
+	}{
+		{name: "file unchanged", content: validYAML, wantHost: "db.internal"},
+		{name: "file changed", content: strings.Replace(validYAML, "host: db.internal", "host: db.reloaded", 1), wantHost: "db.reloaded"},
+		{name: "flagged key changed in the file", content: strings.Replace(validYAML, "port: 5005", "port: 6006", 1), wantHost: "db.internal"},
+		{name: "unknown key warns", content: validYAML + "mailer:\n  hots: smtp\n", wantHost: "db.internal"},
+		{name: "unknown key rejected in strict mode", content: validYAML + "mailer:\n  hots: smtp\n", strict: "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRICT_CONFIG", "")
+			dir := t.TempDir()
+			writeFile(t, dir, "config-staging.yml", validYAML)
+			fs := pflag.NewFlagSet("automart", pflag.ContinueOnError)
+			RegisterFlags(fs)
+			if err := fs.Parse([]string{"--server.port", "9000"}); err != nil {
+				t.Fatal(err)
+			}
+			v, err := LoadConfig("config-staging", "yml", dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := buildConfig(v, "staging", fs)
+			if err != nil {
+				t.Fatalf("buildConfig: %v", err)
+			}
+
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+			t.Setenv("STRICT_CONFIG", tt.strict)
+			writeFile(t, dir, "config-staging.yml", tt.content)
+			var changed []*Config
+			cfg.sharedReloader(func(next *Config) { changed = append(changed, next) }).reload()
+
+			if tt.wantHost == "" {
+				if len(changed) != 0 || !strings.Contains(logs.String(), "unknown config keys: mailer.hots") {
+					t.Errorf("reload accepted; log %q", logs.String())
+				}
+				return
+			}
+			if len(changed) != 1 {
+				t.Fatalf("onChange called %d times, want once; log %q", len(changed), logs.String())
+			}
+			next := cfg.latest()
+			if next.Server.Port != "9000" || next.Postgres.Host != tt.wantHost {
+				t.Errorf("after reload Server.Port %q, Postgres.Host %q; want the 9000 flag and %q",
+					next.Server.Port, next.Postgres.Host, tt.wantHost)
+			}
+			if strings.Contains(logs.String(), "Server.Port") {
+				t.Errorf("reload log %q reports a Server.Port change", logs.String())
+			}
+			if strings.Contains(tt.content, "hots") && !strings.Contains(logs.String(), "ignoring unknown keys mailer.hots") {
+				t.Errorf("log %q does not warn about mailer.hots", logs.String())
+			}
+		})
+	}
+}
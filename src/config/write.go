@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// WriteAs: Writes the effective configuration, after defaults, environment
+// and flag overrides, to w as "yaml" or "json", with secrets redacted. The
+// output uses the same keys as the config files, with durations written as
+// strings such as "15s", so it can be read back by ParseConfigFromReader.
+// It is not called WriteTo, a name go vet reserves for io.WriterTo's
+// WriteTo(w io.Writer) (int64, error).
+
+func (c *Config) WriteAs(w io.Writer, format string) error {
+	redacted := c.Redacted()
+	return writeConfig(w, format, &redacted)
+}
+
+// WriteAsWithSecrets: Like WriteAs, but keeps secret values. Only write the
+// result somewhere as protected as the secrets themselves.
+
+func (c *Config) WriteAsWithSecrets(w io.Writer, format string) error {
+	return writeConfig(w, format, c)
+}
+
+func writeConfig(w io.Writer, format string, c *Config) error {
+	doc := configMap(reflect.ValueOf(c).Elem())
+	switch format {
+	case "yaml", "yml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	default:
+		return fmt.Errorf("unknown config format %q: must be yaml or json", format)
+	}
+}
+
+// configMap: Converts a config struct to a map keyed like the config files,
+// skipping fields that are not read from them.
+
+func configMap(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{})
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.Struct:
+			out[schemaKey(f.Name)] = configMap(field)
+		case field.Type() == reflect.TypeOf(time.Duration(0)):
+			out[schemaKey(f.Name)] = time.Duration(field.Int()).String()
+		default:
+			out[schemaKey(f.Name)] = field.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteAsRoundTrip(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Server.ReadTimeout = 15 * time.Second
+			cfg.Cors.AllowedOrigins = []string{"https://automart.example"}
+			disable := true
+			cfg.Logger.DisableColor = &disable
+			cfg.Logger.Levels = map[string]string{"gorm": "warn"}
+
+			var buf bytes.Buffer
+			if err := cfg.WriteAsWithSecrets(&buf, format); err != nil {
+				t.Fatalf("WriteAsWithSecrets: %v", err)
+			}
+			if format == "yaml" && !strings.Contains(buf.String(), "readTimeout: 15s") {
+				t.Errorf("durations not written as strings:\n%s", buf.String())
+			}
+
+			fileType := format
+			if format == "yaml" {
+				fileType = "yml"
+			}
+			back, err := ParseConfigFromReader(&buf, fileType)
+			if err != nil {
+				t.Fatalf("ParseConfigFromReader: %v", err)
+			}
+			if diff := cfg.Diff(back); len(diff) > 0 {
+				t.Errorf("round trip changed %q", diff)
+			}
+		})
+	}
+}
+
+func TestWriteAsRedactsSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(c *Config, buf *bytes.Buffer) error
+		secrets bool
+	}{
+		{name: "WriteAs", write: func(c *Config, buf *bytes.Buffer) error { return c.WriteAs(buf, "yaml") }},
+		{name: "WriteAsWithSecrets", write: func(c *Config, buf *bytes.Buffer) error { return c.WriteAsWithSecrets(buf, "yaml") }, secrets: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			var buf bytes.Buffer
+			if err := tt.write(cfg, &buf); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+			for _, secret := range []string{"pg-secret", "redis-secret", "0123456789abcdef0123456789abcdef-test"} {
+				if strings.Contains(out, secret) != tt.secrets {
+					t.Errorf("output contains %s = %v, want %v", secret, !tt.secrets, tt.secrets)
+				}
+			}
+			if cfg.Postgres.Password != "pg-secret" {
+				t.Error("writing modified the Config")
+			}
+		})
+	}
+}
+
+func TestWriteAsRejectsUnknownFormat(t *testing.T) {
+	err := validConfig(t).WriteAs(&bytes.Buffer{}, "toml")
+	if err == nil || !strings.Contains(err.Error(), `unknown config format "toml"`) {
+		t.Errorf("WriteAs error = %v, want an unknown format error", err)
+	}
+}
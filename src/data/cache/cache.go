@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"automart/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCacheMiss is returned by Get when the key does not exist.
+var ErrCacheMiss = errors.New("cache: key not found")
+
+// Cache is a key/value store with per-key expiry.
+type Cache interface {
+	// Get returns the value stored under key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl; a zero ttl uses the cache's default.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the keys; missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+	// Exists reports whether key is present.
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+type redisCache struct {
+	rdb        redis.UniversalClient
+	defaultTTL time.Duration
+}
+
+// NewRedisCache returns a Cache backed by rdb that applies cfg.DefaultTTL when
+// Set is called with a zero ttl. A zero DefaultTTL stores such keys without
+// expiry.
+func NewRedisCache(rdb redis.UniversalClient, cfg config.RedisConfig) Cache {
+	return &redisCache{rdb: rdb, defaultTTL: cfg.DefaultTTL}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache get %s: %w", key, err)
+	}
+	return b, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+	if err := c.rdb.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("cache set %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("cache delete: %w", err)
+	}
+	return nil
+}
+
+func (c *redisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("cache exists %s: %w", key, err)
+	}
+	return n > 0, nil
+}
+
+// GetJSON reads key from c and unmarshals it into dst.
+func GetJSON(ctx context.Context, c Cache, key string, dst interface{}) error {
+	b, err := c.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("cache decode %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetJSON marshals v and stores it under key for ttl.
+func SetJSON(ctx context.Context, c Cache, key string, v interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache encode %s: %w", key, err)
+	}
+	return c.Set(ctx, key, b, ttl)
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type listing struct {
+	ID    int
+	Model string
+	Price int64
+}
+
+func newTestCache(t *testing.T, defaultTTL time.Duration) (Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewRedisCache(rdb, config.RedisConfig{DefaultTTL: defaultTTL}), mr
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	c, _ := newTestCache(t, 0)
+	ctx := context.Background()
+
+	want := listing{ID: 7, Model: "Corolla", Price: 1_250_000}
+	if err := SetJSON(ctx, c, "listing:7", want, time.Minute); err != nil {
+		t.Fatalf("SetJSON: %v", err)
+	}
+	var got listing
+	if err := GetJSON(ctx, c, "listing:7", &got); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if got != want {
+		t.Errorf("GetJSON = %+v, want %+v", got, want)
+	}
+
+	if err := GetJSON(ctx, c, "listing:8", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("GetJSON of a missing key = %v, want ErrCacheMiss", err)
+	}
+	if err := c.Set(ctx, "broken", []byte("{"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetJSON(ctx, c, "broken", &got); err == nil || errors.Is(err, ErrCacheMiss) {
+		t.Errorf("GetJSON of invalid JSON = %v, want a decode error", err)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaultTTL time.Duration
+		ttl        time.Duration
+		wantTTL    time.Duration
+	}{
+		{name: "explicit ttl", defaultTTL: time.Hour, ttl: time.Minute, wantTTL: time.Minute},
+		{name: "zero ttl uses the default", defaultTTL: time.Hour, wantTTL: time.Hour},
+		{name: "no default keeps the key", wantTTL: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mr := newTestCache(t, tt.defaultTTL)
+			ctx := context.Background()
+			if err := c.Set(ctx, "k", []byte("v"), tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+			if got := mr.TTL("k"); got != tt.wantTTL {
+				t.Errorf("TTL = %s, want %s", got, tt.wantTTL)
+			}
+
+			if tt.wantTTL == 0 {
+				return
+			}
+			mr.FastForward(tt.wantTTL)
+			if ok, err := c.Exists(ctx, "k"); err != nil || ok {
+				t.Errorf("Exists after expiry = %v, %v; want false", ok, err)
+			}
+			if _, err := c.Get(ctx, "k"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("Get after expiry = %v, want ErrCacheMiss", err)
+			}
+		})
+	}
+}
+
+func TestDeleteAndExists(t *testing.T) {
+	c, _ := newTestCache(t, 0)
+	ctx := context.Background()
+	for _, k := range []string{"a", "b"} {
+		if err := c.Set(ctx, k, []byte(k), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Delete(ctx, "a", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := c.Delete(ctx); err != nil {
+		t.Fatalf("Delete without keys: %v", err)
+	}
+	for k, want := range map[string]bool{"a": false, "b": true} {
+		if ok, err := c.Exists(ctx, k); err != nil || ok != want {
+			t.Errorf("Exists(%q) = %v, %v; want %v", k, ok, err, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"automart/config"
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// noopCache stores nothing: every Get misses and every write succeeds.
+type noopCache struct{}
+
+// NewNoopCache returns a Cache that stores nothing, for running without Redis.
+func NewNoopCache() Cache {
+	return noopCache{}
+}
+
+func (noopCache) Get(context.Context, string) ([]byte, error) {
+	return nil, ErrCacheMiss
+}
+
+func (noopCache) Set(context.Context, string, []byte, time.Duration) error {
+	return nil
+}
+
+func (noopCache) Delete(context.Context, ...string) error {
+	return nil
+}
+
+func (noopCache) Exists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// NewCache connects to Redis with NewRedisClient and wraps the client in a
+// Cache. If Redis cannot be reached and cfg.Optional is set, it logs a
+// warning and returns a no-op Cache instead of failing, so the application
+// runs uncached. RedisClient returns the client behind the result for the
+// parts that need Redis itself.
+func NewCache(ctx context.Context, cfg config.RedisConfig) (Cache, error) {
+	rdb, err := NewRedisClient(ctx, cfg)
+	if err != nil {
+		if cfg.Optional {
+			log.Printf("cache: %v; Redis is optional, continuing without a cache", err)
+			return NewNoopCache(), nil
+		}
+		return nil, err
+	}
+	return NewRedisCache(rdb, cfg), nil
+}
+
+// RedisClient returns the client behind a Cache built by NewRedisCache or
+// NewCache, or nil for the no-op Cache, i.e. when running without Redis.
+func RedisClient(c Cache) redis.UniversalClient {
+	if rc, ok := c.(*redisCache); ok {
+		return rc.rdb
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestNewCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		down     bool
+		optional bool
+		wantErr  bool
+		wantNoop bool
+	}{
+		{name: "reachable"},
+		{name: "reachable and optional", optional: true},
+		{name: "unreachable fails fast", down: true, wantErr: true},
+		{name: "unreachable and optional degrades to no-op", down: true, optional: true, wantNoop: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			cfg := testRedisConfig(mr)
+			cfg.Optional = tt.optional
+			if tt.down {
+				mr.Close()
+			}
+
+			c, err := NewCache(context.Background(), cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewCache succeeded without Redis")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCache: %v", err)
+			}
+			rdb := RedisClient(c)
+			if (rdb == nil) != tt.wantNoop {
+				t.Fatalf("RedisClient() = %v, want nil only for the no-op cache", rdb)
+			}
+			if rdb != nil {
+				defer rdb.Close()
+			}
+
+			ctx := context.Background()
+			if err := c.Set(ctx, "k", []byte("v"), 0); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			got, err := c.Get(ctx, "k")
+			if tt.wantNoop {
+				if !errors.Is(err, ErrCacheMiss) {
+					t.Errorf("no-op Get = %q, %v, want ErrCacheMiss", got, err)
+				}
+				if ok, err := c.Exists(ctx, "k"); ok || err != nil {
+					t.Errorf("no-op Exists = %v, %v, want false", ok, err)
+				}
+				if err := c.Delete(ctx, "k"); err != nil {
+					t.Errorf("no-op Delete: %v", err)
+				}
+				return
+			}
+			if err != nil || string(got) != "v" {
+				t.Errorf("Get = %q, %v, want v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"automart/config"
+	"automart/internal/retry"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedisNotConfigured is returned by NewRedisClient when neither Host nor
+// Port is set, which an optional Redis config uses to turn Redis off.
+var ErrRedisNotConfigured = errors.New("redis is not configured")
+
+// NewRedisClient builds a go-redis client for cfg.Mode and pings the server
+// to confirm it is reachable: a plain client for "single", a Sentinel-backed
+// failover client for "sentinel" and a cluster client for "cluster". A failed
+// ping is retried cfg.ConnectRetries times with jittered exponential backoff
+// starting at cfg.ConnectRetryDelay. Each ping is bounded by cfg.DialTimeout
+// and the whole attempt stops as soon as ctx is done. The client honours
+// context deadlines on every command; without that go-redis would wait out
+// ReadTimeout instead.
+//
+// IdleCheckFrequency has no go-redis v9 counterpart: idle connections are
+// checked when they are taken from the pool rather than by a background reaper.
+func NewRedisClient(ctx context.Context, cfg config.RedisConfig) (redis.UniversalClient, error) {
+	if !cfg.Configured() {
+		return nil, ErrRedisNotConfigured
+	}
+	rdb, err := newUniversalClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = retry.Do(ctx, "redis", cfg.ConnectRetries, cfg.ConnectRetryDelay, func(ctx context.Context) error {
+		return ping(ctx, rdb, cfg.DialTimeout)
+	})
+	if err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("ping redis %s: %w", describe(cfg), err)
+	}
+	return rdb, nil
+}
+
+// ping pings rdb, bounded by timeout when it is positive.
+func ping(ctx context.Context, rdb redis.UniversalClient, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return rdb.Ping(ctx).Err()
+}
+
+func newUniversalClient(cfg config.RedisConfig) (redis.UniversalClient, error) {
+	db, err := cfg.DBIndex()
+	if err != nil {
+		return nil, err
+	}
+	readTimeout := commandTimeout(cfg.ReadTimeout, cfg.AllowZeroTimeouts)
+	writeTimeout := commandTimeout(cfg.WriteTimeout, cfg.AllowZeroTimeouts)
+
+	switch cfg.Mode {
+	case "", config.RedisModeSingle:
+		return redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr(),
+			Password:     cfg.Password,
+			DB:           db,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  readTimeout,
+			WriteTimeout: writeTimeout,
+			PoolSize:     cfg.PoolSize,
+			PoolTimeout:  cfg.PoolTimeout,
+			MinIdleConns: cfg.MinIdleConnections,
+
+			ContextTimeoutEnabled: true,
+		}), nil
+	case config.RedisModeSentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.SentinelAddrs,
+			Password:      cfg.Password,
+			DB:            db,
+			DialTimeout:   cfg.DialTimeout,
+			ReadTimeout:   readTimeout,
+			WriteTimeout:  writeTimeout,
+			PoolSize:      cfg.PoolSize,
+			PoolTimeout:   cfg.PoolTimeout,
+			MinIdleConns:  cfg.MinIdleConnections,
+
+			ContextTimeoutEnabled: true,
+		}), nil
+	case config.RedisModeCluster:
+		// Redis Cluster only has database 0, so Db is not used.
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.ClusterAddrs,
+			Password:     cfg.Password,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  readTimeout,
+			WriteTimeout: writeTimeout,
+			PoolSize:     cfg.PoolSize,
+			PoolTimeout:  cfg.PoolTimeout,
+			MinIdleConns: cfg.MinIdleConnections,
+
+			ContextTimeoutEnabled: true,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", cfg.Mode)
+	}
+}
+
+// commandTimeout maps an allowed zero timeout to -1, which go-redis reads as
+// "no deadline"; it would otherwise treat zero as its 3s default.
+func commandTimeout(d time.Duration, allowZero bool) time.Duration {
+	if d == 0 && allowZero {
+		return -1
+	}
+	return d
+}
+
+// describe names the Redis endpoint in error messages.
+func describe(cfg config.RedisConfig) string {
+	switch cfg.Mode {
+	case config.RedisModeSentinel:
+		return fmt.Sprintf("master %s via sentinels %s (db %d)", cfg.MasterName, strings.Join(cfg.SentinelAddrs, ", "), cfg.Db)
+	case config.RedisModeCluster:
+		return "cluster " + strings.Join(cfg.ClusterAddrs, ", ")
+	default:
+		return fmt.Sprintf("at %s (db %d)", cfg.Addr(), cfg.Db)
+	}
+}
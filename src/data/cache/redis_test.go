@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"automart/config"
+	"automart/internal/testutil"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testRedisConfig returns a single-mode config pointing at mr.
+func testRedisConfig(mr *miniredis.Miniredis) config.RedisConfig {
+	host, port, _ := net.SplitHostPort(mr.Addr())
+	return config.RedisConfig{
+		Mode:               config.RedisModeSingle,
+		Host:               host,
+		Port:               port,
+		DialTimeout:        time.Second,
+		ReadTimeout:        2 * time.Second,
+		WriteTimeout:       3 * time.Second,
+		PoolSize:           7,
+		PoolTimeout:        4 * time.Second,
+		MinIdleConnections: 2,
+	}
+}
+
+func TestNewRedisClient(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cfg := testRedisConfig(mr)
+	cfg.Db = 2
+
+	rdb, err := NewRedisClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewRedisClient: %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Set(context.Background(), "k", "v", 0).Err(); err != nil {
+		t.Fatalf("SET: %v", err)
+	}
+	mr.Select(2)
+	if got, _ := mr.Get("k"); got != "v" {
+		t.Errorf("key not written to db 2, got %q", got)
+	}
+
+	opts := rdb.(*redis.Client).Options()
+	if opts.Addr != mr.Addr() || opts.DB != 2 || opts.PoolSize != 7 || opts.MinIdleConns != 2 ||
+		opts.DialTimeout != time.Second || opts.ReadTimeout != 2*time.Second ||
+		opts.WriteTimeout != 3*time.Second || opts.PoolTimeout != 4*time.Second {
+		t.Errorf("options not forwarded: %+v", opts)
+	}
+}
+
+func TestNewRedisClientPingFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cfg := testRedisConfig(mr)
+	mr.Close()
+
+	_, err := NewRedisClient(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "ping redis at "+cfg.Addr()) {
+		t.Fatalf("NewRedisClient error = %v, want a ping error naming %s", err, cfg.Addr())
+	}
+}
+
+func TestNewRedisClientNotConfigured(t *testing.T) {
+	_, err := NewRedisClient(context.Background(), config.RedisConfig{Optional: true})
+	if !errors.Is(err, ErrRedisNotConfigured) {
+		t.Errorf("NewRedisClient error = %v, want ErrRedisNotConfigured", err)
+	}
+}
+
+func TestNewRedisClientHonoursDeadlines(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctxTimeout  time.Duration
+		dialTimeout time.Duration
+	}{
+		{name: "context deadline", ctxTimeout: 100 * time.Millisecond, dialTimeout: time.Minute},
+		{name: "DialTimeout", ctxTimeout: time.Minute, dialTimeout: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := testutil.Blackhole(t)
+			cfg := config.RedisConfig{
+				Host:         host,
+				Port:         port,
+				DialTimeout:  tt.dialTimeout,
+				ReadTimeout:  time.Minute,
+				WriteTimeout: time.Minute,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := NewRedisClient(ctx, cfg)
+			if err == nil {
+				t.Fatal("NewRedisClient succeeded against a server that never answers")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("NewRedisClient returned after %s, want about 100ms", elapsed)
+			}
+		})
+	}
+}
+
+func TestNewUniversalClientModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.RedisConfig
+		check   func(t *testing.T, rdb redis.UniversalClient)
+		wantErr string
+	}{
+		{
+			name: "single by default",
+			cfg:  config.RedisConfig{Host: "cache", Port: "6379"},
+			check: func(t *testing.T, rdb redis.UniversalClient) {
+				if c, ok := rdb.(*redis.Client); !ok || c.Options().Addr != "cache:6379" {
+					t.Errorf("got %T, want a single-node client for cache:6379", rdb)
+				}
+			},
+		},
+		{
+			name: "sentinel",
+			cfg:  config.RedisConfig{Mode: config.RedisModeSentinel, MasterName: "mymaster", SentinelAddrs: []string{"s1:26379", "s2:26379"}, Db: 3},
+			check: func(t *testing.T, rdb redis.UniversalClient) {
+				// go-redis returns a *redis.Client whose address is resolved via Sentinel.
+				if c, ok := rdb.(*redis.Client); !ok || c.Options().DB != 3 {
+					t.Errorf("got %T, want a failover client on db 3", rdb)
+				}
+			},
+		},
+		{
+			name: "cluster",
+			cfg:  config.RedisConfig{Mode: config.RedisModeCluster, ClusterAddrs: []string{"n1:6379", "n2:6379"}},
+			check: func(t *testing.T, rdb redis.UniversalClient) {
+				if c, ok := rdb.(*redis.ClusterClient); !ok || len(c.Options().Addrs) != 2 {
+					t.Errorf("got %T, want a cluster client for two nodes", rdb)
+				}
+			},
+		},
+		{name: "unknown mode", cfg: config.RedisConfig{Mode: "ring"}, wantErr: `unknown redis mode "ring"`},
+		{name: "db out of range", cfg: config.RedisConfig{Host: "cache", Port: "6379", Db: 16}, wantErr: "invalid Redis.Db 16"},
+		{name: "db within Databases", cfg: config.RedisConfig{Host: "cache", Port: "6379", Db: 20, Databases: 32},
+			check: func(t *testing.T, rdb redis.UniversalClient) {
+				if c, ok := rdb.(*redis.Client); !ok || c.Options().DB != 20 {
+					t.Errorf("got %T, want a client on db 20", rdb)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, err := newUniversalClient(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("newUniversalClient error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newUniversalClient: %v", err)
+			}
+			defer rdb.Close()
+			tt.check(t, rdb)
+		})
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	tests := []struct {
+		d         time.Duration
+		allowZero bool
+		want      time.Duration
+	}{
+		{d: time.Second, want: time.Second},
+		{d: 0, want: 0},
+		{d: 0, allowZero: true, want: -1},
+		{d: time.Second, allowZero: true, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := commandTimeout(tt.d, tt.allowZero); got != tt.want {
+			t.Errorf("commandTimeout(%s, %v) = %s, want %s", tt.d, tt.allowZero, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package data
+
+import (
+	"automart/data/db"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// CloseAll closes the Postgres pool and the Redis client, skipping any that
+// is nil, and reports every failure.
+func CloseAll(database *gorm.DB, rdb redis.UniversalClient) error {
+	var errs []error
+	if database != nil {
+		if err := db.CloseGormDB(database); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if rdb != nil {
+		if err := rdb.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close redis: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func openMocks(t *testing.T) (*gorm.DB, redis.UniversalClient) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectClose()
+	database, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := miniredis.RunT(t)
+	return database, redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func TestCloseAll(t *testing.T) {
+	database, rdb := openMocks(t)
+	if err := CloseAll(database, rdb); err != nil {
+		t.Fatalf("CloseAll: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := database.WithContext(ctx).Exec("SELECT 1").Error; err == nil || !strings.Contains(err.Error(), "database is closed") {
+		t.Errorf("query after CloseAll = %v, want a closed database error", err)
+	}
+	if err := rdb.Ping(ctx).Err(); !errors.Is(err, redis.ErrClosed) {
+		t.Errorf("ping after CloseAll = %v, want redis.ErrClosed", err)
+	}
+
+	err := CloseAll(nil, rdb)
+	if err == nil || !strings.Contains(err.Error(), "close redis") {
+		t.Errorf("second CloseAll = %v, want the redis close error", err)
+	}
+}
+
+func TestCloseAllSkipsNil(t *testing.T) {
+	if err := CloseAll(nil, nil); err != nil {
+		t.Errorf("CloseAll(nil, nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"automart/config"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// RunMigrations runs GORM AutoMigrate for models when cfg.AutoMigrate is set
+// and does nothing otherwise. Each table is logged as created or updated.
+func RunMigrations(db *gorm.DB, cfg config.MigrationConfig, models ...interface{}) error {
+	if !cfg.AutoMigrate {
+		log.Printf("migrations: AutoMigrate disabled, skipping %d models", len(models))
+		return nil
+	}
+
+	migrator := db.Migrator()
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		existed := migrator.HasTable(model)
+		if err := migrator.AutoMigrate(model); err != nil {
+			return fmt.Errorf("migrate table %s: %w", table, err)
+		}
+		if existed {
+			log.Printf("migrations: updated table %s", table)
+		} else {
+			log.Printf("migrations: created table %s", table)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"automart/config"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+type car struct {
+	ID    uint
+	Model string
+}
+
+func TestRunMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "disabled runs nothing"},
+		{name: "enabled creates missing tables", enabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, mock := newMock(t)
+			db, err := gorm.Open(mockDialector(conn), &gorm.Config{DisableAutomaticPing: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.enabled {
+				for i := 0; i < 2; i++ {
+					mock.ExpectQuery(`information_schema\.tables`).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				}
+				mock.ExpectExec(`CREATE TABLE "cars"`).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			if err := RunMigrations(db, config.MigrationConfig{AutoMigrate: tt.enabled}, &car{}); err != nil {
+				t.Fatalf("RunMigrations: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"automart/config"
+	"automart/internal/retry"
+	"automart/pkg/logging"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// NewGormDB opens a Postgres connection pool from cfg, applies the pool
+// settings and pings the server before returning. The ping is retried with
+// exponential backoff according to cfg.ConnectRetries and cfg.ConnectRetryDelay,
+// which covers Postgres still starting up next to the app (e.g. Docker Compose).
+// Each ping is bounded by cfg.ConnectTimeout, and the whole attempt stops as
+// soon as ctx is done. GORM logs through logger, see logging.NewGormLogger,
+// or with its default stdout logger when logger is nil.
+func NewGormDB(ctx context.Context, cfg config.PostgresConfig, logger *zap.Logger) (*gorm.DB, error) {
+	if err := checkConnParams("Postgres", cfg.Host, cfg.PortInt); err != nil {
+		return nil, err
+	}
+	var gormLogger gormlogger.Interface
+	if logger != nil {
+		gormLogger = logging.NewGormLogger(logger, cfg)
+	}
+	return openPool(ctx, openPostgres(cfg.DSN()), cfg, "postgres", gormLogger)
+}
+
+// openPostgres returns the dialector for a DSN; tests swap it for sqlmock.
+var openPostgres = postgres.Open
+
+// openPool opens a pool through dialector, applies the pool settings of cfg
+// and pings it with retries, using name in log and error messages. A nil
+// gormLogger keeps GORM's default.
+func openPool(ctx context.Context, dialector gorm.Dialector, cfg config.PostgresConfig, name string, gormLogger gormlogger.Interface) (*gorm.DB, error) {
+	// gorm's own ping on Open would bypass ctx and the retry loop below.
+	db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true, Logger: gormLogger})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("get %s pool: %w", name, err)
+	}
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(connMaxIdleTime(cfg))
+
+	err = retry.Do(ctx, name, cfg.ConnectRetries, cfg.ConnectRetryDelay, func(ctx context.Context) error {
+		return ping(ctx, sqlDB, cfg.ConnectTimeout)
+	})
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("ping %s at %s:%s: %w", name, cfg.Host, cfg.Port, err)
+	}
+	return db, nil
+}
+
+// ping pings sqlDB, bounded by timeout when it is positive.
+func ping(ctx context.Context, sqlDB *sql.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CloseGormDB closes the connection pool underlying db.
+func CloseGormDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("get postgres pool: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("close postgres: %w", err)
+	}
+	return nil
+}
+
+// defaultConnMaxIdleTime applies when PostgresConfig.ConnMaxIdleTime is zero.
+const defaultConnMaxIdleTime = 5 * time.Minute
+
+func connMaxIdleTime(cfg config.PostgresConfig) time.Duration {
+	if cfg.ConnMaxIdleTime == 0 {
+		return defaultConnMaxIdleTime
+	}
+	return cfg.ConnMaxIdleTime
+}
+
+// checkConnParams fails fast on settings that cannot produce a working DSN,
+// naming the field instead of leaving it to a driver error. portInt is the
+// PortInt method of the section. Config.Validate covers the same ground;
+// this also guards configs built without it.
+func checkConnParams(section, host string, portInt func() (int, error)) error {
+	if strings.TrimSpace(host) == "" {
+		return fmt.Errorf("invalid %s.Host: must not be empty", section)
+	}
+	if _, err := portInt(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"automart/config"
+	"automart/internal/testutil"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newMock returns a sqlmock connection that expects pings to be declared.
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, mock
+}
+
+// mockDialector opens GORM on conn as a Postgres database.
+func mockDialector(conn *sql.DB) gorm.Dialector {
+	return postgres.New(postgres.Config{Conn: conn})
+}
+
+func testPostgresConfig() config.PostgresConfig {
+	return config.PostgresConfig{
+		Host:            "db",
+		Port:            "5432",
+		User:            "app",
+		DbName:          "automart",
+		MaxIdleConns:    3,
+		MaxOpenConns:    7,
+		ConnMaxLifetime: time.Minute,
+	}
+}
+
+func TestOpenPoolAppliesPoolSettings(t *testing.T) {
+	conn, mock := newMock(t)
+	mock.ExpectPing()
+
+	cfg := testPostgresConfig()
+	db, err := openPool(context.Background(), mockDialector(conn), cfg, "postgres", nil)
+	if err != nil {
+		t.Fatalf("openPool: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sqlDB.Stats().MaxOpenConnections; got != cfg.MaxOpenConns {
+		t.Errorf("MaxOpenConnections = %d, want %d", got, cfg.MaxOpenConns)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("connectivity was not verified: %v", err)
+	}
+}
+
+func TestOpenPoolWrapsPingFailure(t *testing.T) {
+	conn, mock := newMock(t)
+	refused := errors.New("connection refused")
+	mock.ExpectPing().WillReturnError(refused)
+
+	cfg := testPostgresConfig()
+	cfg.ConnectRetries = 0
+	_, err := openPool(context.Background(), mockDialector(conn), cfg, "postgres", nil)
+	if !errors.Is(err, refused) {
+		t.Fatalf("openPool error = %v, want it to wrap %v", err, refused)
+	}
+	if !strings.Contains(err.Error(), "ping postgres at db:5432") {
+		t.Errorf("error %q does not name the server", err)
+	}
+}
+
+func TestOpenPoolRetriesPing(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		retries  int
+		wantErr  string
+	}{
+		{name: "succeeds after failures", failures: 2, retries: 2},
+		{name: "gives up", failures: 3, retries: 2, wantErr: "giving up after 3 attempts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, mock := newMock(t)
+			for i := 0; i < tt.failures; i++ {
+				mock.ExpectPing().WillReturnError(errors.New("the database system is starting up"))
+			}
+			if tt.wantErr == "" {
+				mock.ExpectPing()
+			}
+
+			cfg := testPostgresConfig()
+			cfg.ConnectRetries = tt.retries
+			cfg.ConnectRetryDelay = time.Millisecond
+			_, err := openPool(context.Background(), mockDialector(conn), cfg, "postgres", nil)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("openPool: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("openPool error = %v, want %q", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNewGormDBHonoursDeadlines(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctxTimeout     time.Duration
+		connectTimeout time.Duration
+	}{
+		{name: "context deadline", ctxTimeout: 100 * time.Millisecond, connectTimeout: time.Minute},
+		{name: "ConnectTimeout", ctxTimeout: time.Minute, connectTimeout: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testPostgresConfig()
+			cfg.Host, cfg.Port = testutil.Blackhole(t)
+			cfg.SSLMode = "disable"
+			cfg.ConnectTimeout = tt.connectTimeout
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := NewGormDB(ctx, cfg, nil)
+			if err == nil {
+				t.Fatal("NewGormDB succeeded against a server that never answers")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("NewGormDB returned after %s, want about 100ms", elapsed)
+			}
+		})
+	}
+}
+
+func TestNewGormDBRejectsBadConnParams(t *testing.T) {
+	tests := []struct {
+		host, port, wantErr string
+	}{
+		{host: "", port: "5432", wantErr: "invalid Postgres.Host: must not be empty"},
+		{host: "db", port: "0", wantErr: `invalid Postgres.Port "0"`},
+	}
+	for _, tt := range tests {
+		cfg := testPostgresConfig()
+		cfg.Host, cfg.Port = tt.host, tt.port
+		_, err := NewGormDB(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewGormDB(%q, %q) error = %v, want %q", tt.host, tt.port, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckConnParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		port    string
+		section string
+		wantErr string
+	}{
+		{name: "valid", host: "db", port: "5432", section: "Postgres"},
+		{name: "port with spaces", host: "db", port: " 5432 ", section: "Postgres"},
+		{name: "empty host", host: "", port: "5432", section: "Postgres", wantErr: "invalid Postgres.Host: must not be empty"},
+		{name: "blank host", host: "  ", port: "5432", section: "Postgres", wantErr: "invalid Postgres.Host: must not be empty"},
+		{name: "non-numeric port", host: "db", port: "postgres", section: "Postgres", wantErr: `invalid Postgres.Port "postgres": must be a port number between 1 and 65535`},
+		{name: "port out of range", host: "db", port: "70000", section: "Postgres", wantErr: `invalid Postgres.Port "70000"`},
+		{name: "replica names its section", host: "replica", port: "x", section: "PostgresReplica", wantErr: `invalid PostgresReplica.Port "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testPostgresConfig()
+			cfg.Host, cfg.Port = tt.host, tt.port
+			portInt := cfg.PortInt
+			if tt.section == "PostgresReplica" {
+				portInt = config.PostgresReplicaConfig(cfg).PortInt
+			}
+			err := checkConnParams(tt.section, cfg.Host, portInt)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("checkConnParams: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("checkConnParams error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConnMaxIdleTime(t *testing.T) {
+	tests := []struct {
+		set, want time.Duration
+	}{
+		{set: 0, want: defaultConnMaxIdleTime},
+		{set: 30 * time.Second, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		cfg := testPostgresConfig()
+		cfg.ConnMaxIdleTime = tt.set
+		if got := connMaxIdleTime(cfg); got != tt.want {
+			t.Errorf("connMaxIdleTime(%s) = %s, want %s", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestOpenPoolReapsIdleConnections(t *testing.T) {
+	conn, mock := newMock(t)
+	mock.ExpectPing()
+
+	cfg := testPostgresConfig()
+	cfg.ConnMaxIdleTime = 10 * time.Millisecond
+	db, err := openPool(context.Background(), mockDialector(conn), cfg, "postgres", nil)
+	if err != nil {
+		t.Fatalf("openPool: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// database/sql checks idle connections at most once a second.
+	deadline := time.Now().Add(3 * time.Second)
+	for sqlDB.Stats().MaxIdleTimeClosed == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("idle connection not closed after ConnMaxIdleTime: %+v", sqlDB.Stats())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
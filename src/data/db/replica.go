@@ -0,0 +1,81 @@
+package db
+
+import (
+	"automart/config"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// NewGormDBWithReplica opens the primary like NewGormDB and, when a replica is
+// configured, registers it with GORM's dbresolver so queries go to the
+// replica and writes and transactions to the primary. The replica gets its
+// own pool and is pinged with retries like the primary.
+// SSLMode, pool and connect settings left empty on the replica are taken
+// from the primary. Without a replica it is exactly NewGormDB.
+func NewGormDBWithReplica(ctx context.Context, primary config.PostgresConfig, replica config.PostgresReplicaConfig, logger *zap.Logger) (*gorm.DB, error) {
+	if replica.Configured() {
+		if err := checkConnParams("PostgresReplica", replica.Host, replica.PortInt); err != nil {
+			return nil, err
+		}
+	}
+	db, err := NewGormDB(ctx, primary, logger)
+	if err != nil || !replica.Configured() {
+		return db, err
+	}
+
+	r := config.PostgresConfig(replica)
+	if r.SSLMode == "" {
+		r.SSLMode = primary.SSLMode
+	}
+	if r.MaxIdleConns == 0 {
+		r.MaxIdleConns = primary.MaxIdleConns
+	}
+	if r.MaxOpenConns == 0 {
+		r.MaxOpenConns = primary.MaxOpenConns
+	}
+	if r.ConnMaxLifetime == 0 {
+		r.ConnMaxLifetime = primary.ConnMaxLifetime
+	}
+	if r.ConnMaxIdleTime == 0 {
+		r.ConnMaxIdleTime = primary.ConnMaxIdleTime
+	}
+
+	if r.ConnectTimeout == 0 {
+		r.ConnectTimeout = primary.ConnectTimeout
+	}
+	if r.ConnectRetries == 0 {
+		r.ConnectRetries = primary.ConnectRetries
+	}
+	if r.ConnectRetryDelay == 0 {
+		r.ConnectRetryDelay = primary.ConnectRetryDelay
+	}
+
+	// The replica pool is opened and configured here and handed to dbresolver
+	// as an existing connection: the resolver's own pool setters would apply
+	// to the primary as well.
+	replicaDB, err := openPool(ctx, openPostgres(r.DSN()), r, "postgres replica", nil)
+	if err != nil {
+		_ = CloseGormDB(db)
+		return nil, err
+	}
+	replicaSQL, err := replicaDB.DB()
+	if err != nil {
+		_ = CloseGormDB(db)
+		return nil, fmt.Errorf("get postgres replica pool: %w", err)
+	}
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{postgres.New(postgres.Config{Conn: replicaSQL})},
+		Policy:   dbresolver.RandomPolicy{},
+	})
+	if err := db.Use(resolver); err != nil {
+		_ = replicaSQL.Close()
+		_ = CloseGormDB(db)
+		return nil, fmt.Errorf("register postgres replica: %w", err)
+	}
+	return db, nil
+}
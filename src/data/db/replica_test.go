@@ -0,0 +1,87 @@
+package db
+
+import (
+	"automart/config"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// mockPostgresDSNs makes openPostgres open a sqlmock connection for each of
+// dsns instead of dialling Postgres.
+func mockPostgresDSNs(t *testing.T, dsns ...string) []sqlmock.Sqlmock {
+	t.Helper()
+	var mocks []sqlmock.Sqlmock
+	for _, dsn := range dsns {
+		conn, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.MonitorPingsOption(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		mocks = append(mocks, mock)
+	}
+	previous := openPostgres
+	openPostgres = func(dsn string) gorm.Dialector {
+		return postgres.New(postgres.Config{DriverName: "sqlmock", DSN: dsn})
+	}
+	t.Cleanup(func() { openPostgres = previous })
+	return mocks
+}
+
+func TestNewGormDBWithReplicaRoutesQueries(t *testing.T) {
+	primaryCfg := testPostgresConfig()
+	primaryCfg.SSLMode = "require"
+	replicaCfg := config.PostgresReplicaConfig{Host: "replica", Port: "5432", User: "app", DbName: "automart"}
+	// The replica inherits the primary's SSLMode.
+	replicaDSN := "host=replica port=5432 user=app password='' dbname=automart sslmode=require"
+	mocks := mockPostgresDSNs(t, primaryCfg.DSN(), replicaDSN)
+	primary, replica := mocks[0], mocks[1]
+
+	primary.ExpectPing()
+	replica.ExpectPing()
+	db, err := NewGormDBWithReplica(context.Background(), primaryCfg, replicaCfg, nil)
+	if err != nil {
+		t.Fatalf("NewGormDBWithReplica: %v", err)
+	}
+
+	replica.ExpectQuery(`SELECT \* FROM "cars"`).WillReturnRows(sqlmock.NewRows([]string{"id", "model"}).AddRow(1, "Corolla"))
+	var cars []car
+	if err := db.Find(&cars).Error; err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	primary.ExpectBegin()
+	primary.ExpectQuery(`INSERT INTO "cars"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	primary.ExpectCommit()
+	if err := db.Create(&car{Model: "Civic"}).Error; err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	for name, mock := range map[string]sqlmock.Sqlmock{"primary": primary, "replica": replica} {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestNewGormDBWithoutReplica(t *testing.T) {
+	cfg := testPostgresConfig()
+	mocks := mockPostgresDSNs(t, cfg.DSN())
+	mocks[0].ExpectPing()
+	db, err := NewGormDBWithReplica(context.Background(), cfg, config.PostgresReplicaConfig{}, nil)
+	if err != nil {
+		t.Fatalf("NewGormDBWithReplica: %v", err)
+	}
+
+	mocks[0].ExpectQuery(`SELECT \* FROM "cars"`).WillReturnRows(sqlmock.NewRows([]string{"id", "model"}))
+	var cars []car
+	if err := db.Find(&cars).Error; err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := mocks[0].ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"automart/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 answers the bucket calls NewMinioClient makes: HEAD to check a
+// bucket and PUT to create it. Requests with the wrong access key get 403.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	created []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "Credential=test-access/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	bucket := strings.Trim(r.URL.Path, "/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodHead:
+		if !f.buckets[bucket] {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodPut:
+		f.buckets[bucket] = true
+		f.created = append(f.created, bucket)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewMinioClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    bool
+		accessKey   string
+		wantCreated []string
+		wantErr     string
+	}{
+		{name: "creates a missing bucket", accessKey: "test-access", wantCreated: []string{"car-images"}},
+		{name: "keeps an existing bucket", existing: true, accessKey: "test-access"},
+		{name: "wrong credentials", accessKey: "other", wantErr: "check bucket car-images"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3 := &fakeS3{buckets: map[string]bool{"car-images": tt.existing}}
+			srv := httptest.NewServer(s3)
+			defer srv.Close()
+
+			_, err := NewMinioClient(config.StorageConfig{
+				Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+				AccessKey: tt.accessKey,
+				SecretKey: "test-secret",
+				Bucket:    "car-images",
+				Region:    "us-east-1",
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewMinioClient error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMinioClient: %v", err)
+			}
+			if strings.Join(s3.created, ",") != strings.Join(tt.wantCreated, ",") {
+				t.Errorf("created buckets %v, want %v", s3.created, tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the wait before retry number attempt, counting from 0:
+// base doubled attempt times, capped at max when max is positive. With
+// jitter the result is drawn uniformly from [0, that delay] ("full jitter"),
+// so clients restarted together do not retry in lockstep.
+func Backoff(attempt int, base, max time.Duration, jitter bool) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	d := base
+	for i := 0; i < attempt; i++ {
+		if max > 0 && d >= max {
+			break
+		}
+		if d > math.MaxInt64/2 {
+			// Doubling again would overflow.
+			break
+		}
+		d *= 2
+	}
+	if max > 0 && d > max {
+		d = max
+	}
+	if jitter {
+		return rand.N(d + 1)
+	}
+	return d
+}
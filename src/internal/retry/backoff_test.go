@@ -0,0 +1,57 @@
+package retry
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		base    time.Duration
+		max     time.Duration
+		want    time.Duration
+	}{
+		{name: "first attempt", attempt: 0, base: time.Second, max: time.Minute, want: time.Second},
+		{name: "doubles", attempt: 3, base: time.Second, max: time.Minute, want: 8 * time.Second},
+		{name: "capped at max", attempt: 10, base: time.Second, max: 30 * time.Second, want: 30 * time.Second},
+		{name: "no cap", attempt: 10, base: time.Millisecond, want: 1024 * time.Millisecond},
+		{name: "huge attempt does not overflow", attempt: 1000, base: time.Second, want: time.Second << 33},
+		{name: "huge attempt with a cap", attempt: math.MaxInt32, base: time.Second, max: time.Minute, want: time.Minute},
+		{name: "zero base", attempt: 5, base: 0, max: time.Minute, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Backoff(tt.attempt, tt.base, tt.max, false); got != tt.want {
+				t.Errorf("Backoff(%d, %s, %s) = %s, want %s", tt.attempt, tt.base, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	tests := []struct {
+		attempt   int
+		base, max time.Duration
+	}{
+		{attempt: 0, base: 100 * time.Millisecond, max: time.Second},
+		{attempt: 2, base: 100 * time.Millisecond, max: time.Second},
+		{attempt: 8, base: 100 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		upper := Backoff(tt.attempt, tt.base, tt.max, false)
+		distinct := map[time.Duration]bool{}
+		for i := 0; i < 200; i++ {
+			d := Backoff(tt.attempt, tt.base, tt.max, true)
+			if d < 0 || d > upper {
+				t.Fatalf("Backoff(%d) with jitter = %s, want within [0, %s]", tt.attempt, d, upper)
+			}
+			distinct[d] = true
+		}
+		if len(distinct) < 2 {
+			t.Errorf("Backoff(%d) with jitter always returned the same delay", tt.attempt)
+		}
+	}
+}
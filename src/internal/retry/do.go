@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MaxDelay caps the backoff between the attempts of Do.
+const MaxDelay = 30 * time.Second
+
+// Do calls op until it succeeds, at most retries+1 times, waiting a jittered
+// Backoff from base between attempts. Each failed attempt that is retried is
+// logged as "<name> not ready". Do stops as soon as ctx is done and returns
+// the last error, wrapped with the number of attempts made.
+func Do(ctx context.Context, name string, retries int, base time.Duration, op func(context.Context) error) error {
+	attempts := retries + 1
+	attempt := 1
+	var err error
+	for ; ; attempt++ {
+		if err = op(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts || ctx.Err() != nil {
+			break
+		}
+		delay := Backoff(attempt-1, base, MaxDelay, true)
+		log.Printf("%s not ready (attempt %d/%d): %v; retrying in %s", name, attempt, attempts, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, ctx.Err())
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+}
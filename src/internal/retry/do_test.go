@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	notReady := errors.New("not ready")
+	tests := []struct {
+		name      string
+		retries   int
+		failures  int
+		wantCalls int
+		wantErr   string
+	}{
+		{name: "first attempt succeeds", retries: 3, failures: 0, wantCalls: 1},
+		{name: "succeeds after retries", retries: 3, failures: 2, wantCalls: 3},
+		{name: "gives up", retries: 2, failures: 5, wantCalls: 3, wantErr: "giving up after 3 attempts: not ready"},
+		{name: "no retries", retries: 0, failures: 1, wantCalls: 1, wantErr: "giving up after 1 attempts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), "postgres", tt.retries, time.Millisecond, func(context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return notReady
+				}
+				return nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("op called %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Do: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Do error = %v, want %q", err, tt.wantErr)
+			}
+			if !errors.Is(err, notReady) {
+				t.Errorf("Do error %v does not wrap the last op error", err)
+			}
+		})
+	}
+}
+
+func TestDoStopsWhenCtxIsDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := Do(ctx, "redis", 100, time.Second, func(context.Context) error { return errors.New("refused") })
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do returned after %s, want it to stop at the ctx deadline", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "giving up") {
+		t.Errorf("Do error = %v, want it to give up", err)
+	}
+}
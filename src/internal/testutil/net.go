@@ -0,0 +1,30 @@
+// Package testutil holds helpers shared by the tests of several packages.
+package testutil
+
+import (
+	"net"
+	"testing"
+)
+
+// Blackhole accepts TCP connections and never answers, like a host that is
+// reachable at the TCP level but hangs. It returns the address to dial and
+// stops listening when the test ends.
+func Blackhole(t testing.TB) (host, port string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+	host, port, _ = net.SplitHostPort(l.Addr().String())
+	return host, port
+}
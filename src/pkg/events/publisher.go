@@ -0,0 +1,85 @@
+package events
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publisher sends domain events, such as a listing being created or a
+// message being sent, to the message broker.
+type Publisher interface {
+	// Publish sends body to the configured exchange with routingKey.
+	Publish(ctx context.Context, routingKey string, body []byte) error
+	// Close releases the broker connection.
+	Close() error
+}
+
+// NewPublisher connects to the broker in cfg.URL and returns a Publisher for
+// cfg.Exchange, or a no-op Publisher when cfg.Enabled is false. The exchange
+// must already exist.
+func NewPublisher(cfg config.AMQPConfig) (Publisher, error) {
+	if !cfg.Enabled {
+		return noopPublisher{}, nil
+	}
+	if _, err := amqp.ParseURI(cfg.URL); err != nil {
+		// The URI may contain credentials, so it is not echoed back; a
+		// *url.Error quotes it, only its cause is kept.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid AMQP.URL: %w", err)
+	}
+
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("connect amqp: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("open amqp channel: %w", err)
+	}
+	return &amqpPublisher{cfg: cfg, conn: conn, ch: ch}, nil
+}
+
+type amqpPublisher struct {
+	cfg  config.AMQPConfig
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// Publish sends body as a persistent JSON message. It gives up after
+// cfg.PublishTimeout unless ctx has an earlier deadline.
+func (p *amqpPublisher) Publish(ctx context.Context, routingKey string, body []byte) error {
+	if p.cfg.PublishTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.PublishTimeout)
+		defer cancel()
+	}
+	err := p.ch.PublishWithContext(ctx, p.cfg.Exchange, routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+	})
+	if err != nil {
+		return fmt.Errorf("publish %s to %s: %w", routingKey, p.cfg.Exchange, err)
+	}
+	return nil
+}
+
+func (p *amqpPublisher) Close() error {
+	return errors.Join(p.ch.Close(), p.conn.Close())
+}
+
+// noopPublisher is used when the broker is disabled: events are dropped.
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, string, []byte) error { return nil }
+
+func (noopPublisher) Close() error { return nil }
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// defaultReadinessTimeout applies when ReadinessHandler gets a zero timeout.
+const defaultReadinessTimeout = 2 * time.Second
+
+type probeResponse struct {
+	Status   string   `json:"status"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// LivenessHandler answers 200 for as long as the process is serving
+// requests. It checks no dependencies, so an outage of Postgres or Redis
+// does not get the pod restarted.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, http.StatusOK, probeResponse{Status: "ok"})
+	}
+}
+
+// ReadinessHandler runs checks in parallel, sharing a deadline of timeout,
+// and answers 200 when all pass or 503 with the failing checks listed in
+// "failures". Checks should name their dependency in the error, as Postgres
+// and Redis do; a check still running at the deadline is reported by its
+// position.
+func ReadinessHandler(timeout time.Duration, checks ...func(context.Context) error) http.HandlerFunc {
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		results := make([]chan error, len(checks))
+		for i, check := range checks {
+			results[i] = make(chan error, 1)
+			go func() { results[i] <- check(ctx) }()
+		}
+
+		var failures []string
+		for i, result := range results {
+			var err error
+			select {
+			case err = <-result:
+			case <-ctx.Done():
+				// Prefer a result that arrived together with the deadline.
+				select {
+				case err = <-result:
+				default:
+					err = fmt.Errorf("check %d: %w", i+1, ctx.Err())
+				}
+			}
+			if err != nil {
+				failures = append(failures, err.Error())
+			}
+		}
+
+		if len(failures) > 0 {
+			writeProbe(w, http.StatusServiceUnavailable, probeResponse{Status: "unavailable", Failures: failures})
+			return
+		}
+		writeProbe(w, http.StatusOK, probeResponse{Status: "ok"})
+	}
+}
+
+// Postgres returns a readiness check pinging db.
+func Postgres(db *gorm.DB) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if err := pingPostgres(ctx, db); err != nil {
+			return fmt.Errorf("postgres: %w", err)
+		}
+		return nil
+	}
+}
+
+// Redis returns a readiness check pinging rdb.
+func Redis(rdb redis.UniversalClient) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		return nil
+	}
+}
+
+func writeProbe(w http.ResponseWriter, status int, body probeResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
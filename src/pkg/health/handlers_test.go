@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// probe calls h and decodes its response.
+func probe(t *testing.T, h http.HandlerFunc) (int, probeResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body probeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", w.Body.String(), err)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("probe response may be cached")
+	}
+	return w.Code, body
+}
+
+func TestLivenessHandler(t *testing.T) {
+	code, body := probe(t, LivenessHandler())
+	if code != http.StatusOK || body.Status != "ok" {
+		t.Errorf("liveness = %d %+v, want 200 ok", code, body)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(name string) func(context.Context) error {
+		return func(context.Context) error { return errors.New(name + ": connection refused") }
+	}
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	tests := []struct {
+		name         string
+		checks       []func(context.Context) error
+		wantStatus   int
+		wantFailures []string
+	}{
+		{name: "no checks", wantStatus: http.StatusOK},
+		{name: "all healthy", checks: []func(context.Context) error{ok, ok}, wantStatus: http.StatusOK},
+		{name: "one failing", checks: []func(context.Context) error{ok, failing("redis")}, wantStatus: http.StatusServiceUnavailable, wantFailures: []string{"redis: connection refused"}},
+		{
+			name:         "all failing in check order",
+			checks:       []func(context.Context) error{failing("postgres"), failing("redis")},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantFailures: []string{"postgres: connection refused", "redis: connection refused"},
+		},
+		{name: "check past the deadline", checks: []func(context.Context) error{ok, hanging}, wantStatus: http.StatusServiceUnavailable, wantFailures: []string{"check 2: context deadline exceeded"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			code, body := probe(t, ReadinessHandler(50*time.Millisecond, tt.checks...))
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("readiness took %s, want it bounded by the timeout", elapsed)
+			}
+			if code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", code, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(body.Failures, tt.wantFailures) {
+				t.Errorf("failures = %q, want %q", body.Failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestDependencyChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(t *testing.T) func(context.Context) error
+		wantErr string
+	}{
+		{name: "healthy postgres", check: func(t *testing.T) func(context.Context) error { return Postgres(mockDB(t, nil, 0)) }},
+		{name: "failing postgres", check: func(t *testing.T) func(context.Context) error { return Postgres(mockDB(t, errors.New("refused"), 0)) }, wantErr: "postgres: refused"},
+		{name: "healthy redis", check: func(t *testing.T) func(context.Context) error { return Redis(redisClient(t, false)) }},
+		{name: "failing redis", check: func(t *testing.T) func(context.Context) error { return Redis(redisClient(t, true)) }, wantErr: "redis: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(t)(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("check: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("check error = %v, want it to start with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
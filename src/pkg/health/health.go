@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Check pings Postgres and Redis in parallel and reports every dependency
+// that failed. It returns as soon as ctx is done, even if a ping is still
+// blocked, so a slow dependency cannot stall a readiness probe. A nil rdb
+// (Redis optional and unavailable at startup) is not checked.
+func Check(ctx context.Context, db *gorm.DB, rdb redis.UniversalClient) error {
+	checks := map[string]func(context.Context) error{
+		"postgres": func(ctx context.Context) error { return pingPostgres(ctx, db) },
+	}
+	if rdb != nil {
+		checks["redis"] = func(ctx context.Context) error { return rdb.Ping(ctx).Err() }
+	}
+
+	results := make(chan error, len(checks))
+	for name, check := range checks {
+		go func() {
+			if err := check(ctx); err != nil {
+				results <- fmt.Errorf("%s: %w", name, err)
+				return
+			}
+			results <- nil
+		}()
+	}
+
+	var errs []error
+	for range checks {
+		select {
+		case err := <-results:
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-ctx.Done():
+			return errors.Join(append(errs, fmt.Errorf("health check: %w", ctx.Err()))...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func pingPostgres(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
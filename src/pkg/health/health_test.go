@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// mockDB returns a GORM handle whose next ping fails with pingErr, or is
+// delayed by delay.
+func mockDB(t *testing.T, pingErr error, delay time.Duration) *gorm.DB {
+	t.Helper()
+	conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	mock.ExpectPing().WillReturnError(pingErr).WillDelayFor(delay)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// redisClient returns a client for a miniredis server, which is shut down
+// first when down is true.
+func redisClient(t *testing.T, down bool) redis.UniversalClient {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialerRetries: 1})
+	t.Cleanup(func() { _ = rdb.Close() })
+	if down {
+		mr.Close()
+	}
+	return rdb
+}
+
+func TestCheck(t *testing.T) {
+	refused := errors.New("connection refused")
+	tests := []struct {
+		name      string
+		pingErr   error
+		pingDelay time.Duration
+		redisDown bool
+		noRedis   bool
+		want      []string
+	}{
+		{name: "both healthy"},
+		{name: "redis not configured", noRedis: true},
+		{name: "failing postgres", pingErr: refused, want: []string{"postgres: connection refused"}},
+		{name: "failing redis", redisDown: true, want: []string{"redis: "}},
+		{name: "both failing", pingErr: refused, redisDown: true, want: []string{"postgres: connection refused", "redis: "}},
+		{name: "slow postgres", pingDelay: time.Second, want: []string{"health check: context deadline exceeded"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mockDB(t, tt.pingErr, tt.pingDelay)
+			var rdb redis.UniversalClient
+			if !tt.noRedis {
+				rdb = redisClient(t, tt.redisDown)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := Check(ctx, db, rdb)
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Check took %s, want it bounded by the context deadline", elapsed)
+			}
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Check: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Check succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Check error %q does not contain %q", err, want)
+				}
+			}
+			if tt.pingErr != nil && !errors.Is(err, tt.pingErr) {
+				t.Errorf("Check error does not wrap %v", tt.pingErr)
+			}
+		})
+	}
+}
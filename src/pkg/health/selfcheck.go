@@ -0,0 +1,57 @@
+package health
+
+import (
+	"automart/config"
+	"automart/data/cache"
+	"automart/data/db"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// SelfCheck validates c and then connects to Postgres, including the read
+// replica when configured, and Redis, closing each connection again. Every
+// step is logged with its result and all failures are returned together, so
+// a preflight run (e.g. --check-config in CI/CD) shows everything that needs
+// fixing at once.
+func SelfCheck(ctx context.Context, c *config.Config) error {
+	steps := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"config", func(context.Context) error { return c.Validate() }},
+		{"postgres", func(ctx context.Context) error {
+			database, err := openDB(ctx, c.Postgres, c.PostgresReplica, nil)
+			if err != nil {
+				return err
+			}
+			return db.CloseGormDB(database)
+		}},
+		{"redis", func(ctx context.Context) error {
+			rdb, err := cache.NewRedisClient(ctx, c.Redis)
+			if errors.Is(err, cache.ErrRedisNotConfigured) && c.Redis.Optional {
+				log.Printf("self-check: redis: not configured and optional, skipping")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return rdb.Close()
+		}},
+	}
+
+	var errs []error
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			log.Printf("self-check: %s: FAILED: %v", step.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		log.Printf("self-check: %s: ok", step.name)
+	}
+	return errors.Join(errs...)
+}
+
+// openDB opens the Postgres pool checked by SelfCheck; tests swap it for sqlmock.
+var openDB = db.NewGormDBWithReplica
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"automart/config"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const selfCheckYAML = `
+server:
+  port: 5005
+auth:
+  secretKey: 0123456789abcdef0123456789abcdef-test
+postgres:
+  host: db.internal
+  port: 5432
+  user: automart
+  dbName: automart
+  sslMode: disable
+`
+
+// healthyDB makes openDB return a pool that answers one ping.
+func healthyDB(t *testing.T) {
+	t.Helper()
+	orig := openDB
+	t.Cleanup(func() { openDB = orig })
+	openDB = func(context.Context, config.PostgresConfig, config.PostgresReplicaConfig, *zap.Logger) (*gorm.DB, error) {
+		conn, mock, err := sqlmock.New()
+		if err != nil {
+			return nil, err
+		}
+		mock.ExpectClose()
+		return gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	}
+}
+
+// closedPort returns a local port nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	_ = l.Close()
+	return port
+}
+
+func TestSelfCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		realDB     bool
+		redisDown  bool
+		noRedis    bool
+		optional   bool
+		invalid    bool
+		want       []string
+		wantAbsent []string
+	}{
+		{name: "healthy"},
+		{name: "unreachable postgres", realDB: true, want: []string{"postgres: ping postgres at 127.0.0.1:"}, wantAbsent: []string{"redis:"}},
+		{name: "unreachable redis", redisDown: true, want: []string{"redis: ping redis at"}, wantAbsent: []string{"postgres:"}},
+		{name: "optional redis not configured", noRedis: true, optional: true},
+		{name: "required redis not configured", noRedis: true, want: []string{"redis: "}},
+		{name: "failures are aggregated", realDB: true, redisDown: true, invalid: true, want: []string{"config: ", "postgres: ", "redis: "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.ParseConfigFromReader(strings.NewReader(selfCheckYAML), "yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.realDB {
+				cfg.Postgres.Host, cfg.Postgres.Port = "127.0.0.1", closedPort(t)
+			} else {
+				healthyDB(t)
+			}
+			if !tt.noRedis {
+				mr := miniredis.RunT(t)
+				cfg.Redis.Host, cfg.Redis.Port, _ = net.SplitHostPort(mr.Addr())
+				if tt.redisDown {
+					mr.Close()
+				}
+			}
+			cfg.Postgres.ConnectRetries, cfg.Redis.ConnectRetries = 0, 0
+			cfg.Redis.Optional = tt.optional
+			if tt.invalid {
+				cfg.Server.RunMode = "prod"
+			}
+
+			err = SelfCheck(context.Background(), cfg)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("SelfCheck: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("SelfCheck succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(err.Error(), absent) {
+					t.Errorf("error %q reports %q, which is healthy", err, absent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"automart/config"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout bounds a whole request when HTTPClientConfig.Timeout is zero.
+const defaultTimeout = 30 * time.Second
+
+// NewHTTPClient builds an *http.Client for calls to upstream services such as
+// payment or SMS providers. The transport starts from http.DefaultTransport,
+// so proxy settings and dial timeouts are kept, and the pool sizes and idle
+// timeout from cfg replace its defaults when set.
+func NewHTTPClient(cfg config.HTTPClientConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
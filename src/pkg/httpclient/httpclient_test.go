@@ -0,0 +1,57 @@
+package httpclient
+
+import (
+	"automart/config"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	base := http.DefaultTransport.(*http.Transport)
+	tests := []struct {
+		name            string
+		cfg             config.HTTPClientConfig
+		timeout         time.Duration
+		maxIdle         int
+		maxIdlePerHost  int
+		idleConnTimeout time.Duration
+	}{
+		{
+			name:            "defaults",
+			timeout:         30 * time.Second,
+			maxIdle:         base.MaxIdleConns,
+			maxIdlePerHost:  base.MaxIdleConnsPerHost,
+			idleConnTimeout: base.IdleConnTimeout,
+		},
+		{
+			name:            "configured",
+			cfg:             config.HTTPClientConfig{Timeout: 5 * time.Second, MaxIdleConns: 50, MaxIdleConnsPerHost: 10, IdleConnTimeout: 45 * time.Second},
+			timeout:         5 * time.Second,
+			maxIdle:         50,
+			maxIdlePerHost:  10,
+			idleConnTimeout: 45 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewHTTPClient(tt.cfg)
+			if client.Timeout != tt.timeout {
+				t.Errorf("Timeout = %s, want %s", client.Timeout, tt.timeout)
+			}
+			tr, ok := client.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Transport is %T, want *http.Transport", client.Transport)
+			}
+			if tr == base {
+				t.Fatal("client shares http.DefaultTransport")
+			}
+			if tr.MaxIdleConns != tt.maxIdle || tr.MaxIdleConnsPerHost != tt.maxIdlePerHost || tr.IdleConnTimeout != tt.idleConnTimeout {
+				t.Errorf("transport = %d/%d/%s, want %d/%d/%s", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout, tt.maxIdle, tt.maxIdlePerHost, tt.idleConnTimeout)
+			}
+			if tr.Proxy == nil {
+				t.Error("proxy settings of the default transport were dropped")
+			}
+		})
+	}
+}
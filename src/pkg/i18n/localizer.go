@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"automart/config"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/text/language"
+)
+
+// Localizer picks a supported locale for a request and looks up translated
+// messages in per-locale bundles.
+type Localizer struct {
+	defaultLocale string
+	locales       []string
+	matcher       language.Matcher
+	bundles       map[string]map[string]string
+}
+
+// NewLocalizer prepares locale negotiation for cfg.SupportedLocales. When
+// cfg.BundlePath is set, the messages of each locale are read from
+// <BundlePath>/<locale>.json, a flat JSON object of message keys to text;
+// a missing or malformed bundle is an error.
+func NewLocalizer(cfg config.I18nConfig) (*Localizer, error) {
+	// The default locale goes first: the matcher falls back to the first tag.
+	locales := []string{cfg.DefaultLocale}
+	for _, l := range cfg.SupportedLocales {
+		if l != cfg.DefaultLocale {
+			locales = append(locales, l)
+		}
+	}
+
+	tags := make([]language.Tag, 0, len(locales))
+	for _, l := range locales {
+		tag, err := language.Parse(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", l, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	bundles := make(map[string]map[string]string, len(locales))
+	if cfg.BundlePath != "" {
+		for _, l := range locales {
+			bundle, err := loadBundle(filepath.Join(cfg.BundlePath, l+".json"))
+			if err != nil {
+				return nil, err
+			}
+			bundles[l] = bundle
+		}
+	}
+
+	return &Localizer{
+		defaultLocale: cfg.DefaultLocale,
+		locales:       locales,
+		matcher:       language.NewMatcher(tags),
+		bundles:       bundles,
+	}, nil
+}
+
+func loadBundle(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read message bundle: %w", err)
+	}
+	var bundle map[string]string
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, fmt.Errorf("parse message bundle %s: %w", path, err)
+	}
+	return bundle, nil
+}
+
+// Negotiate returns the supported locale that best matches an Accept-Language
+// header such as "fa-IR,fa;q=0.9,en;q=0.8", or the default locale when none
+// matches or the header is empty or malformed.
+func (l *Localizer) Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return l.defaultLocale
+	}
+	_, index, confidence := l.matcher.Match(tags...)
+	if confidence == language.No {
+		return l.defaultLocale
+	}
+	return l.locales[index]
+}
+
+// Message returns the text for key in locale, falling back to the default
+// locale and then to key itself.
+func (l *Localizer) Message(locale, key string) string {
+	if msg, ok := l.bundles[locale][key]; ok {
+		return msg
+	}
+	if msg, ok := l.bundles[l.defaultLocale][key]; ok {
+		return msg
+	}
+	return key
+}
@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"automart/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	l, err := NewLocalizer(config.I18nConfig{DefaultLocale: "en", SupportedLocales: []string{"en", "fa"}})
+	if err != nil {
+		t.Fatalf("NewLocalizer: %v", err)
+	}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "fa", want: "fa"},
+		{header: "fa-IR,fa;q=0.9,en;q=0.8", want: "fa"},
+		{header: "en-US,en;q=0.9", want: "en"},
+		{header: "de-DE,fa;q=0.5", want: "fa"},
+		{header: "de-DE", want: "en"},
+		{header: "", want: "en"},
+		{header: "not;a;header;q=x", want: "en"},
+	}
+	for _, tt := range tests {
+		if got := l.Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"en.json": `{"greeting": "Hello", "only_en": "English only"}`,
+		"fa.json": `{"greeting": "سلام"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l, err := NewLocalizer(config.I18nConfig{DefaultLocale: "en", SupportedLocales: []string{"en", "fa"}, BundlePath: dir})
+	if err != nil {
+		t.Fatalf("NewLocalizer: %v", err)
+	}
+	tests := []struct {
+		locale, key, want string
+	}{
+		{locale: "fa", key: "greeting", want: "سلام"},
+		{locale: "en", key: "greeting", want: "Hello"},
+		{locale: "fa", key: "only_en", want: "English only"},
+		{locale: "fa", key: "missing", want: "missing"},
+	}
+	for _, tt := range tests {
+		if got := l.Message(tt.locale, tt.key); got != tt.want {
+			t.Errorf("Message(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestNewLocalizerErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(`not json`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		cfg     config.I18nConfig
+		wantErr string
+	}{
+		{name: "invalid locale", cfg: config.I18nConfig{DefaultLocale: "en", SupportedLocales: []string{"en", "xx-!!"}}, wantErr: `invalid locale "xx-!!"`},
+		{name: "missing bundle", cfg: config.I18nConfig{DefaultLocale: "en", SupportedLocales: []string{"en", "fa"}, BundlePath: dir}, wantErr: "read message bundle"},
+		{name: "malformed bundle", cfg: config.I18nConfig{DefaultLocale: "en", SupportedLocales: []string{"en", "de"}, BundlePath: dir}, wantErr: "parse message bundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLocalizer(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewLocalizer error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package imaging
+
+import (
+	"automart/config"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// ErrImageTooLarge is returned by ProcessImage for uploads over the
+// configured byte or pixel limit.
+var ErrImageTooLarge = errors.New("image too large")
+
+// defaultJPEGQuality applies when ImageConfig.JPEGQuality is zero.
+const defaultJPEGQuality = 85
+
+// ProcessImage reads an uploaded image, checks its format against
+// cfg.AllowedFormats, scales it down to fit within cfg.MaxWidth by
+// cfg.MaxHeight while keeping its aspect ratio, and re-encodes it in the same
+// format, JPEG at cfg.JPEGQuality. It returns the encoded image and its
+// format ("jpeg", "png" or "gif"). A zero bound leaves that dimension
+// unlimited; images within the bounds are only re-encoded, never enlarged.
+// Animated GIFs are reduced to their first frame. Uploads larger than
+// cfg.MaxBytes, or declaring more than cfg.MaxPixels pixels in their header,
+// are rejected with ErrImageTooLarge before they are decoded.
+func ProcessImage(r io.Reader, cfg config.ImageConfig) ([]byte, string, error) {
+	if cfg.MaxBytes > 0 {
+		// One byte more than allowed tells an oversized upload apart.
+		r = io.LimitReader(r, cfg.MaxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("read image: %w", err)
+	}
+	if cfg.MaxBytes > 0 && int64(len(data)) > cfg.MaxBytes {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", ErrImageTooLarge, cfg.MaxBytes)
+	}
+
+	// Check the format and size from the header before decoding the whole image.
+	header, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unrecognised image: %w", err)
+	}
+	if !allowed(cfg.AllowedFormats, format) {
+		return nil, "", fmt.Errorf("image format %q is not allowed, must be one of %s", format, strings.Join(cfg.AllowedFormats, ", "))
+	}
+	if cfg.MaxPixels > 0 && int64(header.Width)*int64(header.Height) > cfg.MaxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, header.Width, header.Height, cfg.MaxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode %s image: %w", format, err)
+	}
+	img = fit(img, cfg.MaxWidth, cfg.MaxHeight)
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		quality := cfg.JPEGQuality
+		if quality == 0 {
+			quality = defaultJPEGQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case "png":
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		return nil, "", fmt.Errorf("image format %q cannot be re-encoded", format)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("encode %s image: %w", format, err)
+	}
+	return buf.Bytes(), format, nil
+}
+
+// allowed matches format against the configured names, accepting "jpg" for
+// "jpeg" in any case.
+func allowed(formats []string, format string) bool {
+	return slices.ContainsFunc(formats, func(f string) bool {
+		f = strings.ToLower(f)
+		return f == format || (f == "jpg" && format == "jpeg")
+	})
+}
+
+// fit scales img down to fit within maxW by maxH, keeping its aspect ratio.
+func fit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	scale := 1.0
+	if maxW > 0 && w > maxW {
+		scale = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && h > maxH {
+		scale = min(scale, float64(maxH)/float64(h))
+	}
+	if scale == 1 {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(1, int(float64(w)*scale)), max(1, int(float64(h)*scale))))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
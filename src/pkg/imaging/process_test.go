@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"automart/config"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// encoded returns a w by h image in format.
+func encoded(t *testing.T, format string, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, h/2, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessImage(t *testing.T) {
+	base := config.ImageConfig{MaxWidth: 800, MaxHeight: 600, AllowedFormats: []string{"jpg", "png"}}
+	tests := []struct {
+		name       string
+		input      []byte
+		cfg        config.ImageConfig
+		wantFormat string
+		wantW      int
+		wantH      int
+	}{
+		{name: "large PNG is scaled by width", input: encoded(t, "png", 1600, 800), cfg: base, wantFormat: "png", wantW: 800, wantH: 400},
+		{name: "tall JPEG is scaled by height", input: encoded(t, "jpeg", 1000, 3000), cfg: base, wantFormat: "jpeg", wantW: 200, wantH: 600},
+		{name: "small image is not enlarged", input: encoded(t, "png", 20, 10), cfg: base, wantFormat: "png", wantW: 20, wantH: 10},
+		{name: "zero bounds are unlimited", input: encoded(t, "png", 1200, 900), cfg: config.ImageConfig{AllowedFormats: []string{"PNG"}}, wantFormat: "png", wantW: 1200, wantH: 900},
+		{name: "allowed GIF", input: encoded(t, "gif", 1000, 750), cfg: config.ImageConfig{MaxWidth: 800, AllowedFormats: []string{"gif"}}, wantFormat: "gif", wantW: 800, wantH: 600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, format, err := ProcessImage(bytes.NewReader(tt.input), tt.cfg)
+			if err != nil {
+				t.Fatalf("ProcessImage: %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			got, gotFormat, err := image.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("output does not decode: %v", err)
+			}
+			if gotFormat != tt.wantFormat || got.Width != tt.wantW || got.Height != tt.wantH {
+				t.Errorf("output is %s %dx%d, want %s %dx%d", gotFormat, got.Width, got.Height, tt.wantFormat, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestProcessImageRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		cfg      config.ImageConfig
+		wantErr  string
+		tooLarge bool
+	}{
+		{name: "disallowed GIF", input: encoded(t, "gif", 10, 10), cfg: config.ImageConfig{AllowedFormats: []string{"jpg", "png"}}, wantErr: `image format "gif" is not allowed, must be one of jpg, png`},
+		{name: "not an image", input: []byte("hello"), cfg: config.ImageConfig{AllowedFormats: []string{"png"}}, wantErr: "unrecognised image"},
+		{name: "over MaxBytes", input: encoded(t, "png", 200, 200), cfg: config.ImageConfig{AllowedFormats: []string{"png"}, MaxBytes: 100}, wantErr: "more than 100 bytes", tooLarge: true},
+		{name: "over MaxPixels", input: encoded(t, "png", 200, 200), cfg: config.ImageConfig{AllowedFormats: []string{"png"}, MaxPixels: 10000}, wantErr: "200x200 exceeds 10000 pixels", tooLarge: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ProcessImage(bytes.NewReader(tt.input), tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ProcessImage error = %v, want %q", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrImageTooLarge) != tt.tooLarge {
+				t.Errorf("errors.Is(err, ErrImageTooLarge) = %v, want %v", !tt.tooLarge, tt.tooLarge)
+			}
+		})
+	}
+}
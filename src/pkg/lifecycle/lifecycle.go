@@ -0,0 +1,103 @@
+package lifecycle
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultShutdownTimeout applies when ServerConfig.ShutdownTimeout is zero.
+const defaultShutdownTimeout = 10 * time.Second
+
+type entry struct {
+	name  string
+	close func(context.Context) error
+}
+
+// Lifecycle closes the resources opened at startup in reverse order of
+// registration when the process is asked to stop.
+type Lifecycle struct {
+	timeout time.Duration
+
+	mu      sync.Mutex
+	closers []entry
+}
+
+// NewLifecycle returns a Lifecycle whose shutdown is bounded by
+// cfg.ShutdownTimeout.
+func NewLifecycle(cfg config.ServerConfig) *Lifecycle {
+	timeout := cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	return &Lifecycle{timeout: timeout}
+}
+
+// Add registers closer under name. Register each resource right after it is
+// opened so that dependents, added later, are closed before it.
+func (l *Lifecycle) Add(name string, closer func(context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closers = append(l.closers, entry{name: name, close: closer})
+}
+
+// Run blocks until ctx is cancelled or the process receives SIGINT or
+// SIGTERM, then calls Shutdown.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	return l.Shutdown()
+}
+
+// Shutdown calls the registered closers in reverse order, sharing one
+// deadline of the shutdown timeout. A closer still running at the deadline
+// is abandoned and the remaining ones are skipped. All failures are joined
+// into the returned error.
+func (l *Lifecycle) Shutdown() error {
+	l.mu.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mu.Unlock()
+
+	log.Printf("lifecycle: shutting down %d resources (timeout %s)", len(closers), l.timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		c := closers[i]
+		if ctx.Err() != nil {
+			log.Printf("lifecycle: skipped %s: shutdown timeout exceeded", c.name)
+			errs = append(errs, fmt.Errorf("close %s: skipped: %w", c.name, ctx.Err()))
+			continue
+		}
+		if err := closeOne(ctx, c); err != nil {
+			log.Printf("lifecycle: close %s: %v", c.name, err)
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+			continue
+		}
+		log.Printf("lifecycle: closed %s", c.name)
+	}
+	return errors.Join(errs...)
+}
+
+// closeOne runs c in its own goroutine so that a closer ignoring ctx cannot
+// hold up shutdown past the deadline.
+func closeOne(ctx context.Context, c entry) error {
+	done := make(chan error, 1)
+	go func() { done <- c.close(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
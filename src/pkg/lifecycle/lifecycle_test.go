@@ -0,0 +1,158 @@
+package lifecycle
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	failed := errors.New("flush failed")
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		closers   []string // "slow", "stubborn" and "failing" name special closers
+		wantOrder []string
+		wantErr   []string
+		maxTime   time.Duration
+	}{
+		{
+			name:      "reverse order",
+			timeout:   time.Second,
+			closers:   []string{"postgres", "redis", "publisher", "http"},
+			wantOrder: []string{"http", "publisher", "redis", "postgres"},
+		},
+		{
+			name:      "errors are aggregated and do not stop shutdown",
+			timeout:   time.Second,
+			closers:   []string{"postgres", "failing", "http"},
+			wantOrder: []string{"http", "failing", "postgres"},
+			wantErr:   []string{"close failing: flush failed"},
+		},
+		{
+			name:      "slow closer is interrupted by the timeout",
+			timeout:   50 * time.Millisecond,
+			closers:   []string{"postgres", "redis", "slow", "http"},
+			wantOrder: []string{"http", "slow"},
+			wantErr:   []string{"close slow: context deadline exceeded", "close redis: skipped", "close postgres: skipped"},
+			maxTime:   time.Second,
+		},
+		{
+			name:      "closer ignoring ctx is abandoned",
+			timeout:   50 * time.Millisecond,
+			closers:   []string{"postgres", "stubborn"},
+			wantOrder: []string{"stubborn"},
+			wantErr:   []string{"close stubborn: context deadline exceeded", "close postgres: skipped"},
+			maxTime:   time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLifecycle(config.ServerConfig{ShutdownTimeout: tt.timeout})
+			var mu sync.Mutex
+			var order []string
+			release := make(chan struct{})
+			defer close(release)
+			for _, name := range tt.closers {
+				l.Add(name, func(ctx context.Context) error {
+					mu.Lock()
+					order = append(order, name)
+					mu.Unlock()
+					switch name {
+					case "slow":
+						<-ctx.Done()
+						return ctx.Err()
+					case "stubborn":
+						<-release
+					case "failing":
+						return failed
+					}
+					return nil
+				})
+			}
+
+			start := time.Now()
+			err := l.Shutdown()
+			if tt.maxTime > 0 && time.Since(start) > tt.maxTime {
+				t.Errorf("Shutdown took %s, want it bounded by the %s timeout", time.Since(start), tt.timeout)
+			}
+			mu.Lock()
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("closers ran in order %v, want %v", order, tt.wantOrder)
+			}
+			mu.Unlock()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Shutdown: %v", err)
+				}
+				return
+			}
+			for _, want := range tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("Shutdown error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestShutdownRunsClosersOnce(t *testing.T) {
+	l := NewLifecycle(config.ServerConfig{})
+	calls := 0
+	l.Add("postgres", func(context.Context) error { calls++; return nil })
+	_ = l.Shutdown()
+	_ = l.Shutdown()
+	if calls != 1 {
+		t.Errorf("closer called %d times, want 1", calls)
+	}
+}
+
+func TestNewLifecycleTimeout(t *testing.T) {
+	tests := []struct {
+		set, want time.Duration
+	}{
+		{set: 0, want: defaultShutdownTimeout},
+		{set: -time.Second, want: defaultShutdownTimeout},
+		{set: 3 * time.Second, want: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := NewLifecycle(config.ServerConfig{ShutdownTimeout: tt.set}).timeout; got != tt.want {
+			t.Errorf("timeout for ShutdownTimeout %s = %s, want %s", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestRunShutsDownWhenCtxIsCancelled(t *testing.T) {
+	l := NewLifecycle(config.ServerConfig{ShutdownTimeout: time.Second})
+	closed := make(chan struct{})
+	l.Add("http", func(context.Context) error { close(closed); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	select {
+	case <-closed:
+		t.Fatal("closer ran before ctx was cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after ctx was cancelled")
+	}
+	select {
+	case <-closed:
+	default:
+		t.Error("closer not called")
+	}
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	stored := zap.New(core).With(zap.String("request_id", "req-42"))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *zap.Logger
+	}{
+		{name: "round trip", ctx: WithLogger(context.Background(), stored), want: stored},
+		{name: "absent", ctx: context.Background()},
+		{name: "nil logger stored", ctx: WithLogger(context.Background(), nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromContext(tt.ctx)
+			if got == nil {
+				t.Fatal("FromContext returned nil")
+			}
+			if tt.want != nil && got != tt.want {
+				t.Errorf("FromContext returned a different logger")
+			}
+			if tt.want == nil && got.Core().Enabled(zapcore.FatalLevel) {
+				t.Error("fallback logger is not a no-op")
+			}
+		})
+	}
+
+	FromContext(WithLogger(context.Background(), stored)).Info("listing created")
+	if entries := logs.All(); len(entries) != 1 || entries[0].ContextMap()["request_id"] != "req-42" {
+		t.Errorf("logged %v, want one entry tagged with the request ID", entries)
+	}
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type gormLogger struct {
+	l     *zap.Logger
+	slow  time.Duration
+	level gormlogger.LogLevel
+}
+
+// NewGormLogger returns a GORM logger writing to l instead of stdout. Failed
+// queries are logged at error level and queries slower than
+// cfg.SlowQueryThreshold at warn level, both with the SQL, rows affected and
+// duration as fields; other queries are logged at debug level. A zero
+// threshold disables slow-query logging. Record-not-found errors are not
+// logged, since callers handle them as a normal result.
+func NewGormLogger(l *zap.Logger, cfg config.PostgresConfig) gormlogger.Interface {
+	return &gormLogger{
+		l:     l,
+		slow:  cfg.SlowQueryThreshold,
+		level: gormlogger.Info,
+	}
+}
+
+// LogMode returns a copy of the logger filtering at level; zap still applies
+// its own level on top.
+func (g *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *g
+	c.level = level
+	return &c
+}
+
+func (g *gormLogger) Info(_ context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Info {
+		g.l.Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *gormLogger) Warn(_ context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Warn {
+		g.l.Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *gormLogger) Error(_ context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Error {
+		g.l.Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("duration", elapsed),
+		}
+	}
+
+	switch {
+	case err != nil && g.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		g.l.Error("query failed", append(fields(), zap.Error(err))...)
+	case g.slow > 0 && elapsed > g.slow && g.level >= gormlogger.Warn:
+		g.l.Warn("slow query", append(fields(), zap.Duration("threshold", g.slow))...)
+	case g.level >= gormlogger.Info && g.l.Core().Enabled(zap.DebugLevel):
+		g.l.Debug("query", fields()...)
+	}
+}
@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLoggerTrace(t *testing.T) {
+	const query = "SELECT * FROM cars WHERE id = 1"
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		err       error
+		mode      gormlogger.LogLevel
+		wantMsg   string
+		wantLevel zapcore.Level
+	}{
+		{name: "slow query", threshold: 100 * time.Millisecond, elapsed: 200 * time.Millisecond, wantMsg: "slow query", wantLevel: zapcore.WarnLevel},
+		{name: "fast query", threshold: 100 * time.Millisecond, elapsed: 0, wantMsg: "query", wantLevel: zapcore.DebugLevel},
+		{name: "zero threshold disables slow logging", elapsed: time.Second, wantMsg: "query", wantLevel: zapcore.DebugLevel},
+		{name: "failed query", threshold: 100 * time.Millisecond, elapsed: time.Second, err: errors.New("relation does not exist"), wantMsg: "query failed", wantLevel: zapcore.ErrorLevel},
+		{name: "record not found is not an error", err: gorm.ErrRecordNotFound, wantMsg: "query", wantLevel: zapcore.DebugLevel},
+		{name: "silent mode", threshold: 100 * time.Millisecond, elapsed: time.Second, mode: gormlogger.Silent},
+		{name: "warn mode drops plain queries", threshold: 100 * time.Millisecond, mode: gormlogger.Warn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			l := NewGormLogger(zap.New(core), config.PostgresConfig{SlowQueryThreshold: tt.threshold})
+			if tt.mode != 0 {
+				l = l.LogMode(tt.mode)
+			}
+			l.Trace(context.Background(), time.Now().Add(-tt.elapsed), func() (string, int64) { return query, 1 }, tt.err)
+
+			if tt.wantMsg == "" {
+				if logs.Len() != 0 {
+					t.Errorf("logged %+v, want nothing", logs.All())
+				}
+				return
+			}
+			if logs.Len() != 1 {
+				t.Fatalf("logged %d entries, want 1: %+v", logs.Len(), logs.All())
+			}
+			entry := logs.All()[0]
+			if entry.Message != tt.wantMsg || entry.Level != tt.wantLevel {
+				t.Errorf("logged %q at %s, want %q at %s", entry.Message, entry.Level, tt.wantMsg, tt.wantLevel)
+			}
+			fields := entry.ContextMap()
+			if fields["sql"] != query || fields["rows"] != int64(1) {
+				t.Errorf("fields = %v, want the SQL and rows", fields)
+			}
+			if _, ok := fields["duration"]; !ok {
+				t.Errorf("fields = %v, want the duration", fields)
+			}
+		})
+	}
+}
+
+func TestGormLoggerLogsSlowQueryThroughGorm(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	mock.ExpectQuery("SELECT 1").WillDelayFor(50 * time.Millisecond).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               NewGormLogger(zap.New(core), config.PostgresConfig{SlowQueryThreshold: 10 * time.Millisecond}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	slow := logs.FilterMessage("slow query").All()
+	if len(slow) != 1 || slow[0].Level != zapcore.WarnLevel {
+		t.Fatalf("want one slow query warning, got %+v", logs.All())
+	}
+	if sql := slow[0].ContextMap()["sql"]; sql != "SELECT 1" {
+		t.Errorf("sql = %v, want SELECT 1", sql)
+	}
+}
@@ -0,0 +1,173 @@
+package logging
+
+import (
+	"automart/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// LoggerManager hands out zap loggers that share one encoder and set of
+// outputs but may filter at different levels, see Named.
+type LoggerManager struct {
+	cfg     config.LoggerConfig
+	encoder zapcore.Encoder
+	sink    zapcore.WriteSyncer
+	level   zapcore.Level
+	levels  map[string]zapcore.Level
+	opts    []zap.Option
+	root    *zap.Logger
+}
+
+// NewZapLogger builds a zap logger from cfg. Entries are written to stdout
+// and, when cfg.FilePath is set, appended to that file as well. The file is
+// rotated by size once cfg.MaxSizeMB is set. Each second, the sampler keeps
+// the first SamplingInitial entries with the same level and message and
+// every SamplingThereafter-th one after that.
+func NewZapLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
+	m, err := NewLoggerManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return m.Root(), nil
+}
+
+// NewLoggerManager builds the root logger described by cfg, as NewZapLogger
+// does, and prepares the per-name levels in cfg.Levels for Named.
+func NewLoggerManager(cfg config.LoggerConfig) (*LoggerManager, error) {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("logger level: %w", err)
+	}
+	levels := make(map[string]zapcore.Level, len(cfg.Levels))
+	for name, l := range cfg.Levels {
+		lvl, err := zapcore.ParseLevel(l)
+		if err != nil {
+			return nil, fmt.Errorf("logger level for %s: %w", name, err)
+		}
+		levels[strings.ToLower(name)] = lvl
+	}
+
+	encoder, err := newEncoder(cfg.Encoding, useColor(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	sink := zapcore.Lock(os.Stdout)
+	if cfg.FilePath != "" {
+		file, err := newFileSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sink = zapcore.NewMultiWriteSyncer(sink, file)
+	}
+
+	var opts []zap.Option
+	if cfg.EnableCaller {
+		opts = append(opts, zap.AddCaller())
+	}
+	if cfg.StacktraceLevel != "" {
+		stackLevel, err := zapcore.ParseLevel(cfg.StacktraceLevel)
+		if err != nil {
+			return nil, fmt.Errorf("logger stacktrace level: %w", err)
+		}
+		opts = append(opts, zap.AddStacktrace(stackLevel))
+	}
+
+	m := &LoggerManager{cfg: cfg, encoder: encoder, sink: sink, level: level, levels: levels, opts: opts}
+	m.root = m.newLogger(level)
+	return m, nil
+}
+
+// Root returns the unnamed logger, which filters at cfg.Level.
+func (m *LoggerManager) Root() *zap.Logger {
+	return m.root
+}
+
+// Named returns a logger called name that filters at the level configured
+// for it in Levels (e.g. "gorm: warn"), or at the root level otherwise.
+// Names are case-insensitive because viper lower-cases map keys.
+func (m *LoggerManager) Named(name string) *zap.Logger {
+	level, ok := m.levels[strings.ToLower(name)]
+	if !ok {
+		return m.root.Named(name)
+	}
+	return m.newLogger(level).Named(name)
+}
+
+func (m *LoggerManager) newLogger(level zapcore.Level) *zap.Logger {
+	core := zapcore.NewCore(m.encoder, m.sink, zap.NewAtomicLevelAt(level))
+	if m.cfg.SamplingInitial > 0 || m.cfg.SamplingThereafter > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, m.cfg.SamplingInitial, m.cfg.SamplingThereafter)
+	}
+	return zap.New(core, m.opts...)
+}
+
+// newEncoder returns the encoder for encoding; color only affects the
+// console encoding, where it colors the level.
+func newEncoder(encoding string, color bool) (zapcore.Encoder, error) {
+	encCfg := zap.NewProductionEncoderConfig()
+	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(encCfg), nil
+	case "console":
+		if color {
+			encCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		} else {
+			encCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(encCfg), nil
+	default:
+		return nil, fmt.Errorf("unknown logger encoding %q: must be json or console", encoding)
+	}
+}
+
+// useColor reports whether console output is colored: as cfg.DisableColor
+// says when set, otherwise only when writing to a terminal alone, since the
+// file sink shares the encoder.
+func useColor(cfg config.LoggerConfig) bool {
+	if cfg.DisableColor != nil {
+		return !*cfg.DisableColor
+	}
+	fd := os.Stdout.Fd()
+	return cfg.FilePath == "" && (isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd))
+}
+
+// newFileSink returns a lumberjack-backed rotating writer, or a plain
+// append-only file when rotation is not configured.
+func newFileSink(cfg config.LoggerConfig) (zapcore.WriteSyncer, error) {
+	if cfg.MaxSizeMB == 0 {
+		file, err := openLogFile(cfg.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		return zapcore.Lock(file), nil
+	}
+	return zapcore.AddSync(&lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	}), nil
+}
+
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open log file: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,323 @@
+package logging
+
+import (
+	"automart/config"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fileConfig returns a config logging to a file in a temporary directory.
+func fileConfig(t *testing.T, level, encoding string) config.LoggerConfig {
+	t.Helper()
+	disable := true
+	return config.LoggerConfig{
+		Level:        level,
+		Encoding:     encoding,
+		FilePath:     filepath.Join(t.TempDir(), "logs", "app.log"),
+		DisableColor: &disable,
+	}
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestNewZapLogger(t *testing.T) {
+	tests := []struct {
+		level    string
+		encoding string
+		enabled  zapcore.Level
+		disabled zapcore.Level
+	}{
+		{level: "debug", encoding: "json", enabled: zapcore.DebugLevel, disabled: zapcore.DebugLevel - 1},
+		{level: "warn", encoding: "console", enabled: zapcore.WarnLevel, disabled: zapcore.InfoLevel},
+		{level: "error", encoding: "json", enabled: zapcore.ErrorLevel, disabled: zapcore.WarnLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level+"/"+tt.encoding, func(t *testing.T) {
+			cfg := fileConfig(t, tt.level, tt.encoding)
+			logger, err := NewZapLogger(cfg)
+			if err != nil {
+				t.Fatalf("NewZapLogger: %v", err)
+			}
+			if !logger.Core().Enabled(tt.enabled) || logger.Core().Enabled(tt.disabled) {
+				t.Errorf("logger does not filter at %s", tt.level)
+			}
+
+			logger.Error("hello")
+			_ = logger.Sync()
+			line := strings.TrimSpace(readLog(t, cfg.FilePath))
+			var entry map[string]interface{}
+			isJSON := json.Unmarshal([]byte(line), &entry) == nil
+			if isJSON != (tt.encoding == "json") {
+				t.Errorf("%s encoding wrote %q", tt.encoding, line)
+			}
+			if tt.encoding == "console" && !strings.Contains(line, "\tERROR\thello") {
+				t.Errorf("console line %q is not tab separated", line)
+			}
+		})
+	}
+}
+
+func TestNewZapLoggerRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		encoding string
+		wantErr  string
+	}{
+		{name: "unknown encoding", level: "info", encoding: "text", wantErr: `unknown logger encoding "text"`},
+		{name: "unparsable level", level: "verbose", encoding: "json", wantErr: "logger level"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewZapLogger(config.LoggerConfig{Level: tt.level, Encoding: tt.encoding})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewZapLogger error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewFileSinkRotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxSizeMB int
+		wantFiles int
+	}{
+		{name: "plain file without MaxSizeMB", maxSizeMB: 0, wantFiles: 1},
+		{name: "rotates past MaxSizeMB", maxSizeMB: 1, wantFiles: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := fileConfig(t, "info", "json")
+			cfg.MaxSizeMB = tt.maxSizeMB
+			cfg.MaxBackups = 3
+			sink, err := newFileSink(cfg)
+			if err != nil {
+				t.Fatalf("newFileSink: %v", err)
+			}
+
+			line := []byte(strings.Repeat("x", 1023) + "\n")
+			for i := 0; i < 1100; i++ {
+				if _, err := sink.Write(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_ = sink.Sync()
+
+			entries, err := os.ReadDir(filepath.Dir(cfg.FilePath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.wantFiles {
+				t.Errorf("log directory holds %d files, want %d", len(entries), tt.wantFiles)
+			}
+		})
+	}
+}
+
+// logLines runs log against a logger built from cfg and returns the lines
+// written to the log file.
+func logLines(t *testing.T, cfg config.LoggerConfig, log func(l *zap.Logger)) []string {
+	t.Helper()
+	logger, err := NewZapLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewZapLogger: %v", err)
+	}
+	log(logger)
+	_ = logger.Sync()
+	return strings.Split(strings.TrimSpace(readLog(t, cfg.FilePath)), "\n")
+}
+
+func TestNewZapLoggerSampling(t *testing.T) {
+	tests := []struct {
+		name       string
+		initial    int
+		thereafter int
+		want       int
+	}{
+		{name: "disabled when zero", want: 10},
+		{name: "first entries only", initial: 2, want: 2},
+		{name: "first entries and every third after", initial: 2, thereafter: 3, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := fileConfig(t, "info", "json")
+			cfg.SamplingInitial = tt.initial
+			cfg.SamplingThereafter = tt.thereafter
+			lines := logLines(t, cfg, func(l *zap.Logger) {
+				for i := 0; i < 10; i++ {
+					l.Info("same message")
+				}
+			})
+			if len(lines) != tt.want {
+				t.Errorf("%d of 10 entries written, want %d", len(lines), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewZapLoggerCallerAndStacktrace(t *testing.T) {
+	tests := []struct {
+		name           string
+		enableCaller   bool
+		stackLevel     string
+		wantCaller     bool
+		wantStackWarn  bool
+		wantStackError bool
+	}{
+		{name: "neither by default"},
+		{name: "caller", enableCaller: true, wantCaller: true},
+		{name: "stacktrace at error", stackLevel: "error", wantStackError: true},
+		{name: "stacktrace at warn", stackLevel: "warn", wantStackWarn: true, wantStackError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := fileConfig(t, "info", "json")
+			cfg.EnableCaller = tt.enableCaller
+			cfg.StacktraceLevel = tt.stackLevel
+			lines := logLines(t, cfg, func(l *zap.Logger) {
+				l.Warn("warn")
+				l.Error("error")
+			})
+			if len(lines) != 2 {
+				t.Fatalf("got %d lines, want 2", len(lines))
+			}
+			var warn, errEntry map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &warn); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(lines[1]), &errEntry); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := warn["caller"]; ok != tt.wantCaller {
+				t.Errorf("caller present = %v, want %v", ok, tt.wantCaller)
+			}
+			if _, ok := warn["stacktrace"]; ok != tt.wantStackWarn {
+				t.Errorf("stacktrace on warn = %v, want %v", ok, tt.wantStackWarn)
+			}
+			if _, ok := errEntry["stacktrace"]; ok != tt.wantStackError {
+				t.Errorf("stacktrace on error = %v, want %v", ok, tt.wantStackError)
+			}
+		})
+	}
+}
+
+func TestNewZapLoggerRejectsStacktraceLevel(t *testing.T) {
+	cfg := fileConfig(t, "info", "json")
+	cfg.StacktraceLevel = "loud"
+	if _, err := NewZapLogger(cfg); err == nil || !strings.Contains(err.Error(), "logger stacktrace level") {
+		t.Errorf("NewZapLogger error = %v, want a stacktrace level error", err)
+	}
+}
+
+func TestLoggerManagerNamed(t *testing.T) {
+	tests := []struct {
+		name     string
+		logger   string
+		enabled  zapcore.Level
+		disabled zapcore.Level
+	}{
+		{name: "override raises the level", logger: "gorm", enabled: zapcore.WarnLevel, disabled: zapcore.InfoLevel},
+		{name: "override lowers the level", logger: "http", enabled: zapcore.DebugLevel, disabled: zapcore.DebugLevel - 1},
+		{name: "names are case-insensitive", logger: "GORM", enabled: zapcore.WarnLevel, disabled: zapcore.InfoLevel},
+		{name: "unconfigured name uses the root level", logger: "mailer", enabled: zapcore.InfoLevel, disabled: zapcore.DebugLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := fileConfig(t, "info", "json")
+			cfg.Levels = map[string]string{"gorm": "warn", "http": "debug"}
+			m, err := NewLoggerManager(cfg)
+			if err != nil {
+				t.Fatalf("NewLoggerManager: %v", err)
+			}
+			if m.Root().Core().Enabled(zapcore.DebugLevel) {
+				t.Error("root logger does not filter at info")
+			}
+
+			logger := m.Named(tt.logger)
+			if !logger.Core().Enabled(tt.enabled) || logger.Core().Enabled(tt.disabled) {
+				t.Errorf("%s logger does not filter at %s", tt.logger, tt.enabled)
+			}
+			logger.Error("hello")
+			_ = logger.Sync()
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(readLog(t, cfg.FilePath))), &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry["logger"] != tt.logger {
+				t.Errorf("entry logger = %v, want %q", entry["logger"], tt.logger)
+			}
+		})
+	}
+}
+
+func TestNewLoggerManagerRejectsNamedLevel(t *testing.T) {
+	cfg := fileConfig(t, "info", "json")
+	cfg.Levels = map[string]string{"gorm": "chatty"}
+	if _, err := NewLoggerManager(cfg); err == nil || !strings.Contains(err.Error(), "logger level for gorm") {
+		t.Errorf("NewLoggerManager error = %v, want an error naming gorm", err)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	on, off := false, true
+	tests := []struct {
+		name         string
+		disableColor *bool
+		filePath     string
+		want         bool
+	}{
+		{name: "explicitly enabled", disableColor: &on, want: true},
+		{name: "explicitly enabled with a file", disableColor: &on, filePath: "app.log", want: true},
+		{name: "explicitly disabled", disableColor: &off, want: false},
+		{name: "unset with a file", filePath: "app.log", want: false},
+		// go test does not run with stdout attached to a terminal.
+		{name: "unset without a terminal", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := useColor(config.LoggerConfig{DisableColor: tt.disableColor, FilePath: tt.filePath})
+			if got != tt.want {
+				t.Errorf("useColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEncoderColor(t *testing.T) {
+	tests := []struct {
+		encoding  string
+		color     bool
+		wantColor bool
+	}{
+		{encoding: "console", color: true, wantColor: true},
+		{encoding: "console", color: false, wantColor: false},
+		{encoding: "json", color: true, wantColor: false},
+	}
+	for _, tt := range tests {
+		enc, err := newEncoder(tt.encoding, tt.color)
+		if err != nil {
+			t.Fatalf("newEncoder: %v", err)
+		}
+		buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "hello"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(buf.String(), "\x1b["); got != tt.wantColor {
+			t.Errorf("%s encoder with color %v wrote %q, want ANSI colors %v", tt.encoding, tt.color, buf.String(), tt.wantColor)
+		}
+	}
+}
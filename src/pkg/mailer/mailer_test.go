@@ -0,0 +1,124 @@
+package mailer
+
+import (
+	"automart/config"
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// message is what fakeSMTP received for one delivery.
+type message struct {
+	from, rcpt, data string
+}
+
+// fakeSMTP accepts one SMTP conversation without extensions and sends what
+// it received on the returned channel.
+func fakeSMTP(t *testing.T) (host, port string, received <-chan message) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	out := make(chan message, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		var msg message
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch cmd := strings.ToUpper(line); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = line[len("MAIL FROM:"):]
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				msg.rcpt = line[len("RCPT TO:"):]
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				msg.data = data.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				out <- msg
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+	host, port, _ = net.SplitHostPort(l.Addr().String())
+	return host, port, out
+}
+
+func TestSend(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	m, err := NewMailer(config.MailConfig{Host: host, Port: port, From: "AutoMart <noreply@automart.test>"})
+	if err != nil {
+		t.Fatalf("NewMailer: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.Send(ctx, "buyer@example.test", "Listing approved", "Your car is live.\nThanks!"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	msg := <-received
+	if msg.from != "<noreply@automart.test>" || msg.rcpt != "<buyer@example.test>" {
+		t.Errorf("envelope %s -> %s, want <noreply@automart.test> -> <buyer@example.test>", msg.from, msg.rcpt)
+	}
+	for _, want := range []string{
+		"From: \"AutoMart\" <noreply@automart.test>\r\n",
+		"To: <buyer@example.test>\r\n",
+		"Subject: Listing approved\r\n",
+		"\r\n\r\nYour car is live.\r\nThanks!",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestNewMailerRejectsInvalidFrom(t *testing.T) {
+	for _, from := range []string{"", "automart", "noreply@", "<@automart.test>"} {
+		if _, err := NewMailer(config.MailConfig{Host: "smtp.test", Port: "587", From: from}); err == nil || !strings.Contains(err.Error(), "invalid Mail.From") {
+			t.Errorf("NewMailer(From %q) error = %v, want an invalid Mail.From error", from, err)
+		}
+	}
+}
+
+func TestSendRejectsInvalidRecipient(t *testing.T) {
+	m, err := NewMailer(config.MailConfig{Host: "smtp.test", Port: "587", From: "noreply@automart.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), "not an address", "s", "b"); err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+		t.Errorf("Send error = %v, want an invalid recipient error", err)
+	}
+}
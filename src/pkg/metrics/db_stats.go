@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// RegisterDBStats exports the sql.DBStats of db's connection pool, refreshed
+// every interval. Register on a prefixed Registerer (see
+// prometheus.WrapRegistererWithPrefix) to namespace the metrics. The returned
+// function stops the refresh loop.
+func RegisterDBStats(reg prometheus.Registerer, db *gorm.DB, interval time.Duration) (func(), error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("get postgres pool: %w", err)
+	}
+
+	gauge := func(name, help string) prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Subsystem: "db_pool", Name: name, Help: help})
+	}
+	counter := func(name, help string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Subsystem: "db_pool", Name: name, Help: help})
+	}
+	var (
+		maxOpen      = gauge("max_open_connections", "Maximum number of open connections to the database.")
+		open         = gauge("open_connections", "Number of established connections, in use and idle.")
+		inUse        = gauge("in_use_connections", "Number of connections currently in use.")
+		idle         = gauge("idle_connections", "Number of idle connections.")
+		waitCount    = counter("wait_count_total", "Total number of connections waited for.")
+		waitDuration = counter("wait_duration_seconds_total", "Total time blocked waiting for a new connection.")
+	)
+	for _, c := range []prometheus.Collector{maxOpen, open, inUse, idle, waitCount, waitDuration} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("register db pool metrics: %w", err)
+		}
+	}
+
+	// The pool reports cumulative wait totals, so counters advance by the delta.
+	var lastWaitCount int64
+	var lastWaitDuration time.Duration
+	update := func() {
+		s := sqlDB.Stats()
+		maxOpen.Set(float64(s.MaxOpenConnections))
+		open.Set(float64(s.OpenConnections))
+		inUse.Set(float64(s.InUse))
+		idle.Set(float64(s.Idle))
+		waitCount.Add(float64(s.WaitCount - lastWaitCount))
+		waitDuration.Add((s.WaitDuration - lastWaitDuration).Seconds())
+		lastWaitCount, lastWaitDuration = s.WaitCount, s.WaitDuration
+	}
+	return refreshEvery(interval, update), nil
+}
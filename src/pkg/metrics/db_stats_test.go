@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	conn, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	conn.SetMaxOpenConns(4)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewRegistry()
+	stop, err := RegisterDBStats(reg, db, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("RegisterDBStats: %v", err)
+	}
+	defer stop()
+
+	// Hold two connections so they count as in use.
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		c, err := conn.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+	}
+
+	want := map[string]float64{
+		"db_pool_max_open_connections": 4,
+		"db_pool_open_connections":     2,
+		"db_pool_in_use_connections":   2,
+		"db_pool_idle_connections":     0,
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := gathered(t, reg)
+		ok := true
+		for name, v := range want {
+			ok = ok && got[name] == v
+		}
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gauges = %v, want %v", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegisterDBStatsTwice(t *testing.T) {
+	conn, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewRegistry()
+	stop, err := RegisterDBStats(reg, db, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if _, err := RegisterDBStats(reg, db, time.Hour); err == nil {
+		t.Error("registering the same metrics twice succeeded")
+	}
+}
+
+// gathered returns the value of every single-sample metric in reg.
+func gathered(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	for _, mf := range families {
+		if len(mf.GetMetric()) != 1 {
+			continue
+		}
+		m := mf.GetMetric()[0]
+		switch {
+		case m.GetGauge() != nil:
+			values[mf.GetName()] = m.GetGauge().GetValue()
+		case m.GetCounter() != nil:
+			values[mf.GetName()] = m.GetCounter().GetValue()
+		}
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"automart/config"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewMetricsRegistry returns a registry preloaded with the Go runtime and
+// process collectors, and a handler serving it at cfg.Path. When cfg.Enabled
+// is false the registry is still returned, so collectors can register
+// unconditionally, but the handler answers 404 for every request.
+func NewMetricsRegistry(cfg config.MetricsConfig) (*prometheus.Registry, http.Handler) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: cfg.Namespace}),
+	)
+
+	if !cfg.Enabled {
+		return reg, http.NotFoundHandler()
+	}
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	return reg, mux
+}
+
+// refreshEvery calls update now and then every interval until the returned
+// function is called.
+func refreshEvery(interval time.Duration, update func()) func() {
+	update()
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				update()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
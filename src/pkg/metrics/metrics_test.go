@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"automart/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewMetricsRegistry(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.MetricsConfig
+		path   string
+		status int
+	}{
+		{name: "configured path", cfg: config.MetricsConfig{Enabled: true, Path: "/metrics", Namespace: "automart"}, path: "/metrics", status: http.StatusOK},
+		{name: "custom path", cfg: config.MetricsConfig{Enabled: true, Path: "/internal/prom", Namespace: "automart"}, path: "/internal/prom", status: http.StatusOK},
+		{name: "other path", cfg: config.MetricsConfig{Enabled: true, Path: "/internal/prom"}, path: "/metrics", status: http.StatusNotFound},
+		{name: "disabled", cfg: config.MetricsConfig{Path: "/metrics", Namespace: "automart"}, path: "/metrics", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg, handler := NewMetricsRegistry(tt.cfg)
+			// Collectors can register whether or not metrics are served.
+			c := prometheus.NewCounter(prometheus.CounterOpts{Name: "listings_created_total", Help: "test"})
+			reg.MustRegister(c)
+			c.Inc()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("GET %s = %d, want %d", tt.path, w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			for _, want := range []string{"listings_created_total 1", "go_goroutines", "automart_process_"} {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("metrics lack %q", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// RegisterRedisStats exports the go-redis connection pool statistics of rdb,
+// refreshed every interval, like RegisterDBStats does for Postgres. The
+// returned function stops the refresh loop.
+func RegisterRedisStats(reg prometheus.Registerer, rdb redis.UniversalClient, interval time.Duration) (func(), error) {
+	gauge := func(name, help string) prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Subsystem: "redis_pool", Name: name, Help: help})
+	}
+	counter := func(name, help string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Subsystem: "redis_pool", Name: name, Help: help})
+	}
+	var (
+		total    = gauge("total_connections", "Number of connections in the pool.")
+		idle     = gauge("idle_connections", "Number of idle connections in the pool.")
+		hits     = counter("hits_total", "Number of times a free connection was found in the pool.")
+		misses   = counter("misses_total", "Number of times no free connection was found in the pool.")
+		timeouts = counter("timeouts_total", "Number of times waiting for a connection timed out.")
+		stale    = counter("stale_connections_total", "Number of stale connections removed from the pool.")
+	)
+	for _, c := range []prometheus.Collector{total, idle, hits, misses, timeouts, stale} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("register redis pool metrics: %w", err)
+		}
+	}
+
+	// PoolStats are cumulative since the client was created, so counters
+	// advance by the delta.
+	var last redis.PoolStats
+	update := func() {
+		s := rdb.PoolStats()
+		total.Set(float64(s.TotalConns))
+		idle.Set(float64(s.IdleConns))
+		hits.Add(float64(s.Hits - last.Hits))
+		misses.Add(float64(s.Misses - last.Misses))
+		timeouts.Add(float64(s.Timeouts - last.Timeouts))
+		stale.Add(float64(s.StaleConns - last.StaleConns))
+		last = *s
+	}
+	return refreshEvery(interval, update), nil
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRegisterRedisStats(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), PoolSize: 2})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	reg := prometheus.NewRegistry()
+	stop, err := RegisterRedisStats(reg, rdb, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("RegisterRedisStats: %v", err)
+	}
+	defer stop()
+
+	// The first command finds the pool empty, the others reuse its connection.
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := rdb.Set(ctx, "k", i, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := rdb.PoolStats()
+	if want.Hits < 4 || want.Misses < 1 {
+		t.Fatalf("traffic did not reach the pool: %+v", want)
+	}
+
+	tests := []struct {
+		metric string
+		want   float64
+	}{
+		{metric: "redis_pool_hits_total", want: float64(want.Hits)},
+		{metric: "redis_pool_misses_total", want: float64(want.Misses)},
+		{metric: "redis_pool_timeouts_total", want: 0},
+		{metric: "redis_pool_total_connections", want: float64(want.TotalConns)},
+		{metric: "redis_pool_idle_connections", want: float64(want.IdleConns)},
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := gathered(t, reg)
+		ok := true
+		for _, tt := range tests {
+			ok = ok && got[tt.metric] == tt.want
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			for _, tt := range tests {
+				if got[tt.metric] != tt.want {
+					t.Errorf("%s = %v, want %v", tt.metric, got[tt.metric], tt.want)
+				}
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Counters advance by what happened since the last refresh.
+	if err := rdb.Get(ctx, "k").Err(); err != nil {
+		t.Fatal(err)
+	}
+	wantHits := float64(rdb.PoolStats().Hits)
+	for gathered(t, reg)["redis_pool_hits_total"] != wantHits {
+		if time.Now().After(deadline.Add(2 * time.Second)) {
+			t.Fatalf("redis_pool_hits_total = %v, want %v", gathered(t, reg)["redis_pool_hits_total"], wantHits)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegisterRedisStatsTwice(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	reg := prometheus.NewRegistry()
+	stop, err := RegisterRedisStats(reg, rdb, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if _, err := RegisterRedisStats(reg, rdb, time.Hour); err == nil {
+		t.Error("registering the same metrics twice succeeded")
+	}
+}
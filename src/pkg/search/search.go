@@ -0,0 +1,161 @@
+package search
+
+import (
+	"automart/config"
+	"automart/pkg/httpclient"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearchClient indexes and queries documents in a full-text search backend.
+// Index names are given without the configured prefix.
+type SearchClient interface {
+	// Index stores doc under id in index, replacing any previous version.
+	Index(ctx context.Context, index, id string, doc interface{}) error
+	// Search runs an Elasticsearch query DSL body (e.g. map[string]interface{}
+	// {"query": ...}) against index and returns the _source of each hit.
+	Search(ctx context.Context, index string, query interface{}) ([]json.RawMessage, error)
+	// Delete removes id from index; a missing document is not an error.
+	Delete(ctx context.Context, index, id string) error
+}
+
+// NewSearchClient returns a SearchClient speaking the Elasticsearch REST API,
+// which OpenSearch implements as well, or a no-op client when cfg.Enabled is
+// false. Requests go to the first of cfg.Addresses that answers, through an
+// httpclient.NewHTTPClient built from httpCfg, normally Config.HTTPClient.
+func NewSearchClient(cfg config.SearchConfig, httpCfg config.HTTPClientConfig) (SearchClient, error) {
+	if !cfg.Enabled {
+		return noopClient{}, nil
+	}
+	if len(cfg.Addresses) == 0 {
+		return nil, errors.New("search: at least one address is required")
+	}
+	addrs := make([]string, 0, len(cfg.Addresses))
+	for _, a := range cfg.Addresses {
+		u, err := url.Parse(a)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("search: invalid address %q: must be an http or https URL", a)
+		}
+		addrs = append(addrs, strings.TrimSuffix(a, "/"))
+	}
+	return &restClient{
+		cfg:   cfg,
+		addrs: addrs,
+		http:  httpclient.NewHTTPClient(httpCfg),
+	}, nil
+}
+
+type restClient struct {
+	cfg   config.SearchConfig
+	addrs []string
+	http  *http.Client
+}
+
+func (c *restClient) Index(ctx context.Context, index, id string, doc interface{}) error {
+	_, err := c.do(ctx, http.MethodPut, c.indexPath(index)+"/_doc/"+url.PathEscape(id), doc)
+	return err
+}
+
+func (c *restClient) Search(ctx context.Context, index string, query interface{}) ([]json.RawMessage, error) {
+	body, err := c.do(ctx, http.MethodPost, c.indexPath(index)+"/_search", query)
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		Hits struct {
+			Hits []struct {
+				Source json.RawMessage `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("search: decode response: %w", err)
+	}
+	docs := make([]json.RawMessage, 0, len(res.Hits.Hits))
+	for _, h := range res.Hits.Hits {
+		docs = append(docs, h.Source)
+	}
+	return docs, nil
+}
+
+func (c *restClient) Delete(ctx context.Context, index, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, c.indexPath(index)+"/_doc/"+url.PathEscape(id), nil)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// indexPath returns the URL path of index with the configured prefix.
+func (c *restClient) indexPath(index string) string {
+	return "/" + url.PathEscape(c.cfg.IndexPrefix+index)
+}
+
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("search: unexpected status %d: %s", e.code, e.body)
+}
+
+// do sends the request to each address in turn until one answers, and
+// returns the response body of a 2xx answer.
+func (c *restClient) do(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("search: encode request: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, addr := range c.addrs {
+		req, err := http.NewRequestWithContext(ctx, method, addr+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.cfg.Username != "" {
+			req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("search: read response: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, &statusError{code: resp.StatusCode, body: string(respBody)}
+		}
+		return respBody, nil
+	}
+	return nil, fmt.Errorf("search: no address reachable: %w", errors.Join(errs...))
+}
+
+// noopClient is used when search is disabled: writes are dropped and
+// searches find nothing.
+type noopClient struct{}
+
+func (noopClient) Index(context.Context, string, string, interface{}) error { return nil }
+
+func (noopClient) Search(context.Context, string, interface{}) ([]json.RawMessage, error) {
+	return nil, nil
+}
+
+func (noopClient) Delete(context.Context, string, string) error { return nil }
@@ -0,0 +1,229 @@
+package search
+
+import (
+	"automart/config"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc lets a function stand in for the HTTP transport.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func response(code int, body string) *http.Response {
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}
+}
+
+// mockedClient returns a client for cfg whose requests are answered by rt.
+func mockedClient(t *testing.T, cfg config.SearchConfig, rt roundTripFunc) SearchClient {
+	t.Helper()
+	cfg.Enabled = true
+	c, err := NewSearchClient(cfg, config.HTTPClientConfig{})
+	if err != nil {
+		t.Fatalf("NewSearchClient: %v", err)
+	}
+	c.(*restClient).http.Transport = rt
+	return c
+}
+
+func TestNewSearchClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.SearchConfig
+		wantNoop bool
+		wantErr  string
+	}{
+		{name: "disabled is a no-op", cfg: config.SearchConfig{Addresses: []string{"not a url"}}, wantNoop: true},
+		{name: "enabled", cfg: config.SearchConfig{Enabled: true, Addresses: []string{"http://search:9200/"}}},
+		{name: "no addresses", cfg: config.SearchConfig{Enabled: true}, wantErr: "at least one address is required"},
+		{name: "address without scheme", cfg: config.SearchConfig{Enabled: true, Addresses: []string{"search:9200"}}, wantErr: `invalid address "search:9200"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewSearchClient(tt.cfg, config.HTTPClientConfig{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewSearchClient error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSearchClient: %v", err)
+			}
+			if _, noop := c.(noopClient); noop != tt.wantNoop {
+				t.Errorf("client is %T, want no-op %v", c, tt.wantNoop)
+			}
+		})
+	}
+}
+
+func TestNewSearchClientUsesHTTPClientConfig(t *testing.T) {
+	cfg := config.SearchConfig{Enabled: true, Addresses: []string{"http://search:9200"}}
+	c, err := NewSearchClient(cfg, config.HTTPClientConfig{Timeout: 3 * time.Second, MaxIdleConnsPerHost: 7, IdleConnTimeout: time.Minute})
+	if err != nil {
+		t.Fatalf("NewSearchClient: %v", err)
+	}
+	client := c.(*restClient).http
+	transport := client.Transport.(*http.Transport)
+	if client.Timeout != 3*time.Second || transport.MaxIdleConnsPerHost != 7 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("Timeout %s, MaxIdleConnsPerHost %d, IdleConnTimeout %s; want the HTTPClient settings",
+			client.Timeout, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+func TestNoopClient(t *testing.T) {
+	c, _ := NewSearchClient(config.SearchConfig{}, config.HTTPClientConfig{})
+	ctx := context.Background()
+	if err := c.Index(ctx, "cars", "1", map[string]string{"model": "Corolla"}); err != nil {
+		t.Errorf("Index: %v", err)
+	}
+	if docs, err := c.Search(ctx, "cars", nil); err != nil || len(docs) != 0 {
+		t.Errorf("Search = %v, %v, want nothing", docs, err)
+	}
+	if err := c.Delete(ctx, "cars", "1"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
+
+func TestRequestsUseIndexPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c SearchClient) error
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		{
+			name: "Index",
+			call: func(c SearchClient) error {
+				return c.Index(context.Background(), "cars", "42", map[string]string{"model": "Corolla"})
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/automart_cars/_doc/42",
+			wantBody:   `{"model":"Corolla"}`,
+		},
+		{
+			name: "Search",
+			call: func(c SearchClient) error {
+				_, err := c.Search(context.Background(), "cars", map[string]interface{}{"query": map[string]interface{}{"match_all": struct{}{}}})
+				return err
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/automart_cars/_search",
+			wantBody:   `{"query":{"match_all":{}}}`,
+		},
+		{
+			name:       "Delete",
+			call:       func(c SearchClient) error { return c.Delete(context.Background(), "cars", "a/b") },
+			wantMethod: http.MethodDelete,
+			wantPath:   "/automart_cars/_doc/a%2Fb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var body string
+			cfg := config.SearchConfig{Addresses: []string{"http://search:9200"}, Username: "elastic", Password: "changeme", IndexPrefix: "automart_"}
+			c := mockedClient(t, cfg, func(req *http.Request) (*http.Response, error) {
+				got = req
+				b, _ := io.ReadAll(req.Body)
+				body = string(b)
+				return response(http.StatusOK, `{"hits":{"hits":[]}}`), nil
+			})
+			if err := tt.call(c); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got.Method != tt.wantMethod || got.URL.EscapedPath() != tt.wantPath {
+				t.Errorf("request = %s %s, want %s %s", got.Method, got.URL.EscapedPath(), tt.wantMethod, tt.wantPath)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if user, pass, ok := got.BasicAuth(); !ok || user != "elastic" || pass != "changeme" {
+				t.Errorf("basic auth = %q, %q, %v", user, pass, ok)
+			}
+		})
+	}
+}
+
+func TestResponses(t *testing.T) {
+	refused := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		addrs    []string
+		answer   func(req *http.Request) (*http.Response, error)
+		call     func(c SearchClient) (int, error)
+		wantDocs int
+		wantErr  string
+	}{
+		{
+			name:  "Search returns the hits",
+			addrs: []string{"http://search:9200"},
+			answer: func(*http.Request) (*http.Response, error) {
+				return response(http.StatusOK, `{"hits":{"hits":[{"_source":{"id":1}},{"_source":{"id":2}}]}}`), nil
+			},
+			call: func(c SearchClient) (int, error) {
+				docs, err := c.Search(context.Background(), "cars", nil)
+				return len(docs), err
+			},
+			wantDocs: 2,
+		},
+		{
+			name:  "Delete of a missing document",
+			addrs: []string{"http://search:9200"},
+			answer: func(*http.Request) (*http.Response, error) {
+				return response(http.StatusNotFound, `{"result":"not_found"}`), nil
+			},
+			call: func(c SearchClient) (int, error) { return 0, c.Delete(context.Background(), "cars", "1") },
+		},
+		{
+			name:    "error status",
+			addrs:   []string{"http://search:9200"},
+			answer:  func(*http.Request) (*http.Response, error) { return response(http.StatusBadRequest, "bad query"), nil },
+			call:    func(c SearchClient) (int, error) { return 0, c.Index(context.Background(), "cars", "1", nil) },
+			wantErr: "unexpected status 400: bad query",
+		},
+		{
+			name:  "falls over to the next address",
+			addrs: []string{"http://down:9200", "http://up:9200"},
+			answer: func(req *http.Request) (*http.Response, error) {
+				if req.URL.Host == "down:9200" {
+					return nil, refused
+				}
+				return response(http.StatusOK, `{}`), nil
+			},
+			call: func(c SearchClient) (int, error) { return 0, c.Index(context.Background(), "cars", "1", nil) },
+		},
+		{
+			name:    "no address reachable",
+			addrs:   []string{"http://down:9200"},
+			answer:  func(*http.Request) (*http.Response, error) { return nil, refused },
+			call:    func(c SearchClient) (int, error) { return 0, c.Index(context.Background(), "cars", "1", nil) },
+			wantErr: "no address reachable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mockedClient(t, config.SearchConfig{Addresses: tt.addrs}, tt.answer)
+			n, err := tt.call(c)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.wantDocs {
+				t.Errorf("got %d documents, want %d", n, tt.wantDocs)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"automart/config"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestInitTracer(t *testing.T) {
+	var exported atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			exported.Add(1)
+		}
+	}))
+	defer collector.Close()
+
+	tests := []struct {
+		name       string
+		cfg        config.TracingConfig
+		wantErr    string
+		wantExport bool
+	}{
+		{name: "disabled", cfg: config.TracingConfig{Endpoint: collector.URL + "/v1/traces", SampleRatio: 1}},
+		{name: "ratio above 1", cfg: config.TracingConfig{Enabled: true, Endpoint: collector.URL + "/v1/traces", SampleRatio: 1.5}, wantErr: "invalid Tracing.SampleRatio 1.5"},
+		{name: "negative ratio", cfg: config.TracingConfig{Enabled: true, Endpoint: collector.URL + "/v1/traces", SampleRatio: -0.1}, wantErr: "must be between 0 and 1"},
+		{name: "exports sampled spans", cfg: config.TracingConfig{Enabled: true, Endpoint: collector.URL + "/v1/traces", ServiceName: "automart", SampleRatio: 1}, wantExport: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := otel.GetTracerProvider()
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+			exported.Store(0)
+
+			shutdown, err := InitTracer(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("InitTracer error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InitTracer: %v", err)
+			}
+			if !tt.wantExport && otel.GetTracerProvider() != previous {
+				t.Error("a disabled tracer installed a provider")
+			}
+
+			_, span := otel.Tracer("test").Start(context.Background(), "list cars")
+			span.End()
+			if err := shutdown(context.Background()); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+			if got := exported.Load() > 0; got != tt.wantExport {
+				t.Errorf("spans exported: %v, want %v", got, tt.wantExport)
+			}
+		})
+	}
+}